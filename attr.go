@@ -0,0 +1,106 @@
+package expect
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Attribute keys emitted through Attr when an assertion fails and T supports
+// structured test attributes. They appear as "attr" events in go test -json
+// output, immediately before the failure message they describe.
+const (
+	// AttrAssertion holds the name of the failed assertion, such as "Equal".
+	AttrAssertion = "expect.assertion"
+	// AttrLocation holds the file:line of the failed assertion call.
+	AttrLocation = "expect.location"
+	// AttrExpected holds a compact form of the expected value, when there is one.
+	AttrExpected = "expect.expected"
+	// AttrActual holds a compact form of the actual value, when there is one.
+	AttrActual = "expect.actual"
+)
+
+// maxAttrLen limits the length of values emitted as attributes.
+const maxAttrLen = 200
+
+const pkgPath = "github.com/lumertzg/expect"
+
+type attrT interface {
+	Attr(key, value string)
+}
+
+// errorf reports a failure of the named assertion.
+func errorf(t T, assertion, format string, args ...any) {
+	t.Helper()
+	emitAttrs(t, assertion)
+	t.Errorf(format, args...)
+}
+
+// errorfValues is like errorf but also records the expected and actual values.
+func errorfValues(t T, assertion string, expected, actual any, format string, args ...any) {
+	t.Helper()
+	emitAttrs(t, assertion, AttrExpected, compact(expected), AttrActual, compact(actual))
+	t.Errorf(format, args...)
+}
+
+// errorfActual is like errorfValues for assertions that expect no single
+// value, such as NotNil, and only records the actual value.
+func errorfActual(t T, assertion string, actual any, format string, args ...any) {
+	t.Helper()
+	emitAttrs(t, assertion, AttrActual, compact(actual))
+	t.Errorf(format, args...)
+}
+
+// emitAttrs emits the assertion name, its location and the given key/value
+// pairs as test attributes if t supports them.
+func emitAttrs(t T, assertion string, kv ...string) {
+	at, ok := t.(attrT)
+	if !ok {
+		return
+	}
+	at.Attr(AttrAssertion, assertion)
+	if loc := callerLocation(); loc != "" {
+		at.Attr(AttrLocation, loc)
+	}
+	for i := 0; i+1 < len(kv); i += 2 {
+		at.Attr(kv[i], kv[i+1])
+	}
+}
+
+// compact formats value on a single line, truncated to maxAttrLen bytes.
+func compact(value any) string {
+	s := fmt.Sprintf("%v", value)
+	s = strings.NewReplacer("\r", `\r`, "\n", `\n`).Replace(s)
+	if len(s) > maxAttrLen {
+		cut := maxAttrLen
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		s = s[:cut] + "..."
+	}
+	return s
+}
+
+// callerLocation returns the file:line of the first caller outside this
+// package. Test files of this package count as callers.
+func callerLocation() string {
+	var pcs [32]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !inPackage(frame) {
+			return filepath.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+func inPackage(frame runtime.Frame) bool {
+	return strings.HasPrefix(frame.Function, pkgPath+".") && !strings.HasSuffix(frame.File, "_test.go")
+}
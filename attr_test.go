@@ -0,0 +1,100 @@
+package expect
+
+import (
+	"strings"
+	"testing"
+)
+
+type attrMockT struct {
+	mockT
+	attrs map[string]string
+}
+
+func (m *attrMockT) Attr(key, value string) {
+	if m.attrs == nil {
+		m.attrs = map[string]string{}
+	}
+	m.attrs[key] = value
+}
+
+func TestAttr(t *testing.T) {
+	t.Run("pass emits nothing", func(t *testing.T) {
+		m := &attrMockT{}
+		Equal(m, 1, 1)
+		if len(m.attrs) != 0 {
+			t.Errorf("expected no attributes, got %v", m.attrs)
+		}
+	})
+
+	t.Run("fail emits assertion and values", func(t *testing.T) {
+		m := &attrMockT{}
+		Equal(m, 1, 2)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+		if got := m.attrs[AttrAssertion]; got != "Equal" {
+			t.Errorf("expected assertion Equal, got %q", got)
+		}
+		if got := m.attrs[AttrExpected]; got != "1" {
+			t.Errorf("expected expected value 1, got %q", got)
+		}
+		if got := m.attrs[AttrActual]; got != "2" {
+			t.Errorf("expected actual value 2, got %q", got)
+		}
+	})
+
+	t.Run("fail reports caller location", func(t *testing.T) {
+		m := &attrMockT{}
+		NoError(m, errString("boom"))
+		if got := m.attrs[AttrLocation]; !strings.HasPrefix(got, "attr_test.go:") {
+			t.Errorf("expected location in attr_test.go, got %q", got)
+		}
+		if _, ok := m.attrs[AttrExpected]; ok {
+			t.Error("expected no expected value")
+		}
+	})
+
+	t.Run("fail emits actual value", func(t *testing.T) {
+		m := &attrMockT{}
+		Nil(m, 3)
+		if m.attrs[AttrExpected] != "<nil>" || m.attrs[AttrActual] != "3" {
+			t.Errorf("expected values <nil> and 3, got %v", m.attrs)
+		}
+
+		for name, assert := range map[string]func(T){
+			"NotNil":   func(t T) { NotNil(t, (*int)(nil)) },
+			"Empty":    func(t T) { Empty(t, []int{1, 2}) },
+			"NotEmpty": func(t T) { NotEmpty(t, "") },
+		} {
+			m := &attrMockT{}
+			assert(m)
+			if _, ok := m.attrs[AttrActual]; !ok || m.attrs[AttrAssertion] != name {
+				t.Errorf("expected %s to emit an actual value, got %v", name, m.attrs)
+			}
+			if _, ok := m.attrs[AttrExpected]; ok {
+				t.Errorf("expected %s to emit no expected value, got %v", name, m.attrs)
+			}
+		}
+	})
+
+	t.Run("compact values", func(t *testing.T) {
+		m := &attrMockT{}
+		Equal(m, "a\nb", strings.Repeat("x", 300))
+		if got := m.attrs[AttrExpected]; got != `a\nb` {
+			t.Errorf("expected escaped newline, got %q", got)
+		}
+		if got := m.attrs[AttrActual]; len(got) != maxAttrLen+len("...") {
+			t.Errorf("expected truncated value, got %d bytes", len(got))
+		}
+	})
+
+	t.Run("testing.T", func(t *testing.T) {
+		var _ attrT = t
+	})
+}
+
+type errString string
+
+func (e errString) Error() string {
+	return string(e)
+}
@@ -0,0 +1,129 @@
+package expect
+
+import (
+	"slices"
+	"time"
+)
+
+// Receives asserts that a value is received from ch within timeout, and
+// returns it. Receiving from a closed channel fails.
+//
+// Inside a testing/synctest bubble, the other goroutines of the bubble run
+// until they block before ch is checked, and the timeout uses the bubble's
+// fake clock, so the outcome does not depend on scheduling. The same holds
+// for the other channel assertions.
+//
+//	if msg, ok := expect.Receives(t, events, time.Second); ok {
+//		expect.Equal(t, "started", msg.Kind)
+//	}
+func Receives[V any](t T, ch <-chan V, timeout time.Duration) (V, bool) {
+	t.Helper()
+	v, ok, received := receive(ch, timeout)
+	switch {
+	case !received:
+		errorf(t, "Receives", "expected to receive a value within %v, got none", timeout)
+	case !ok:
+		errorf(t, "Receives", "expected to receive a value, but the channel is closed")
+	}
+	return v, ok
+}
+
+// ReceivesValue asserts that expected is the next value received from ch
+// within timeout.
+func ReceivesValue[V comparable](t T, ch <-chan V, expected V, timeout time.Duration) {
+	t.Helper()
+	v, ok, received := receive(ch, timeout)
+	switch {
+	case !received:
+		errorfValues(t, "ReceivesValue", expected, nil, "expected to receive %v within %v, got none", expected, timeout)
+	case !ok:
+		errorfValues(t, "ReceivesValue", expected, nil, "expected to receive %v, but the channel is closed", expected)
+	case v != expected:
+		failMismatch(t, "ReceivesValue", expected, v)
+	}
+}
+
+// NotReceives asserts that no value is received from ch, and that ch is not
+// closed, for the duration of window.
+func NotReceives[V any](t T, ch <-chan V, window time.Duration) {
+	t.Helper()
+	v, ok, received := receive(ch, window)
+	switch {
+	case received && ok:
+		errorf(t, "NotReceives", "expected no value within %v, received %v", window, v)
+	case received:
+		errorf(t, "NotReceives", "expected no value within %v, but the channel was closed", window)
+	}
+}
+
+// Closed asserts that ch is closed within timeout, with no values left to
+// receive. A value received instead is reported and consumed.
+func Closed[V any](t T, ch <-chan V, timeout time.Duration) {
+	t.Helper()
+	v, ok, received := receive(ch, timeout)
+	switch {
+	case !received:
+		errorf(t, "Closed", "expected channel to be closed within %v", timeout)
+	case ok:
+		errorf(t, "Closed", "expected closed channel, received %v", v)
+	}
+}
+
+// NotClosed asserts that ch is not closed, without waiting. If a value is
+// ready to be received, it is consumed.
+func NotClosed[V any](t T, ch <-chan V) {
+	t.Helper()
+	if _, ok, received := receive(ch, 0); received && !ok {
+		errorf(t, "NotClosed", "expected open channel, got closed")
+	}
+}
+
+// DrainsTo asserts that ch yields exactly the values of expected, in order,
+// and is closed within timeout.
+//
+//	expect.DrainsTo(t, stage.Out(), []int{2, 4, 6}, time.Second)
+func DrainsTo[V comparable](t T, ch <-chan V, expected []V, timeout time.Duration) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	var received []V
+	for {
+		v, ok, got := receive(ch, time.Until(deadline))
+		switch {
+		case !got:
+			errorfValues(t, "DrainsTo", expected, received,
+				"expected channel to yield %v and close within %v, received %v and it is still open", expected, timeout, received)
+			return
+		case !ok:
+			if !slices.Equal(expected, received) {
+				failMismatch(t, "DrainsTo", expected, received)
+			}
+			return
+		}
+		received = append(received, v)
+	}
+}
+
+// receive waits up to timeout to receive from ch, and reports whether it
+// received, and whether ch was still open. A ready value or close wins over
+// an expired timeout. Inside a testing/synctest bubble, the other
+// goroutines of the bubble first run until they block.
+func receive[V any](ch <-chan V, timeout time.Duration) (v V, ok, received bool) {
+	settle()
+	select {
+	case v, ok = <-ch:
+		return v, ok, true
+	default:
+	}
+	if timeout <= 0 {
+		return v, false, false
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case v, ok = <-ch:
+		return v, ok, true
+	case <-timer.C:
+		return v, false, false
+	}
+}
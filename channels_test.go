@@ -0,0 +1,194 @@
+package expect
+
+import (
+	"testing"
+	"time"
+)
+
+const (
+	shortWait = 10 * time.Millisecond
+	longWait  = time.Second
+)
+
+func buffered(values ...int) chan int {
+	ch := make(chan int, len(values))
+	for _, v := range values {
+		ch <- v
+	}
+	return ch
+}
+
+func TestReceives(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		ch := make(chan string)
+		go func() { ch <- "hello" }()
+		got, ok := Receives(m, ch, longWait)
+		if m.failed || !ok {
+			t.Error("expected pass")
+		}
+		if got != "hello" {
+			t.Errorf("expected hello, got %q", got)
+		}
+	})
+
+	t.Run("fail timeout", func(t *testing.T) {
+		m := &mockT{}
+		if _, ok := Receives(m, make(chan int), shortWait); !m.failed || ok {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail closed", func(t *testing.T) {
+		m := &mockT{}
+		ch := make(chan int)
+		close(ch)
+		if _, ok := Receives(m, ch, longWait); !m.failed || ok {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestReceivesValue(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		ReceivesValue(m, buffered(1), 1, longWait)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		ReceivesValue(m, buffered(2), 1, longWait)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail timeout", func(t *testing.T) {
+		m := &mockT{}
+		ReceivesValue(m, make(chan int), 1, shortWait)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestNotReceives(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		NotReceives(m, make(chan int), shortWait)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		NotReceives(m, buffered(1), longWait)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail closed", func(t *testing.T) {
+		m := &mockT{}
+		ch := make(chan int)
+		close(ch)
+		NotReceives(m, ch, longWait)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestClosed(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		ch := make(chan int)
+		go close(ch)
+		Closed(m, ch, longWait)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail value", func(t *testing.T) {
+		m := &mockT{}
+		ch := buffered(1)
+		close(ch)
+		Closed(m, ch, longWait)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail timeout", func(t *testing.T) {
+		m := &mockT{}
+		Closed(m, make(chan int), shortWait)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestNotClosed(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		NotClosed(m, make(chan int))
+		NotClosed(m, buffered(1))
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		ch := make(chan int)
+		close(ch)
+		NotClosed(m, ch)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestDrainsTo(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		ch := make(chan int)
+		go func() {
+			defer close(ch)
+			for i := range 3 {
+				ch <- i
+			}
+		}()
+		DrainsTo(m, ch, []int{0, 1, 2}, longWait)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		ch := buffered(1, 3)
+		close(ch)
+		DrainsTo(m, ch, []int{1, 2}, longWait)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail not closed", func(t *testing.T) {
+		m := &mockT{}
+		DrainsTo(m, buffered(1), []int{1}, shortWait)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+		want := "expected channel to yield [1] and close within 10ms, received [1] and it is still open"
+		if m.message != want {
+			t.Errorf("got %q, want %q", m.message, want)
+		}
+	})
+}
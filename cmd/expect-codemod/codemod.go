@@ -0,0 +1,533 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lumertzg/expect/internal/rewrite"
+)
+
+// A problem is a check that looked like a candidate but was left alone.
+type problem struct {
+	Pos     token.Position
+	Message string
+}
+
+func (p problem) String() string {
+	return fmt.Sprintf("%s: %s", p.Pos, p.Message)
+}
+
+// stats counts the outcome of rewriting a set of files.
+type stats struct {
+	Rewritten int
+	Skipped   []problem
+}
+
+// options controls which checks are rewritten.
+type options struct {
+	// force allows dropping failure messages that mention values other
+	// than the ones being compared.
+	force bool
+}
+
+// assertion is the expect call replacing a check.
+type assertion struct {
+	name string
+	args []ast.Expr
+}
+
+// rewriter rewrites the checks of one file.
+type rewriter struct {
+	file  *rewrite.File
+	opts  options
+	stats *stats
+	qual  string
+	used  bool
+}
+
+// rewriteFile replaces hand-written checks in file with expect calls.
+func rewriteFile(file *rewrite.File, opts options, st *stats) {
+	r := &rewriter{file: file, opts: opts, stats: st}
+	switch name := file.ImportName(rewrite.ExpectPath); name {
+	case "", "_":
+		r.qual = "expect."
+	case ".":
+		r.qual = ""
+	default:
+		r.qual = name + "."
+	}
+
+	ast.Inspect(file.AST, func(n ast.Node) bool {
+		if stmt, ok := n.(*ast.IfStmt); ok && r.ifStmt(stmt) {
+			return false
+		}
+		return true
+	})
+
+	if r.used && r.qual == "expect." && file.ImportName(rewrite.ExpectPath) != "expect" {
+		file.AddImport(rewrite.ExpectPath)
+	}
+}
+
+// ifStmt rewrites stmt if it is a recognised check and reports whether it did.
+func (r *rewriter) ifStmt(stmt *ast.IfStmt) bool {
+	report, ok := r.reportCall(stmt)
+	if !ok {
+		return false
+	}
+	if stmt.Else != nil {
+		return false
+	}
+
+	a, ok := r.match(stmt.Cond, report)
+	if !ok {
+		r.skip(stmt, "condition is not a recognised check")
+		return false
+	}
+
+	// Inline a short variable declaration such as err := f() into the
+	// assertion when the variable is only used by the check.
+	var init *ast.AssignStmt
+	if stmt.Init != nil {
+		init, ok = r.inlinableInit(stmt, a)
+		if !ok {
+			r.skip(stmt, "if statement has an initializer that cannot be inlined")
+			return false
+		}
+	}
+
+	if reason := r.checkMessage(report, stmt, a); reason != "" {
+		r.skip(stmt, reason)
+		return false
+	}
+	if r.hasComments(stmt) {
+		r.skip(stmt, "check contains comments")
+		return false
+	}
+
+	recv := report.Fun.(*ast.SelectorExpr).X
+	args := []string{r.file.Source(recv)}
+	for _, arg := range a.args {
+		if init != nil && isIdent(arg, init.Lhs[0].(*ast.Ident).Name) {
+			args = append(args, r.file.Source(init.Rhs[0]))
+			continue
+		}
+		args = append(args, r.file.Source(arg))
+	}
+	r.file.Replace(stmt, r.qual+a.name+"("+strings.Join(args, ", ")+")")
+	r.used = true
+	r.stats.Rewritten++
+	return true
+}
+
+// reportCall returns the t.Error or t.Errorf call that is the only
+// statement in the body of stmt.
+func (r *rewriter) reportCall(stmt *ast.IfStmt) (*ast.CallExpr, bool) {
+	if len(stmt.Body.List) != 1 {
+		return nil, false
+	}
+	expr, ok := stmt.Body.List[0].(*ast.ExprStmt)
+	if !ok {
+		return nil, false
+	}
+	call, ok := expr.X.(*ast.CallExpr)
+	if !ok || call.Ellipsis.IsValid() {
+		return nil, false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || (sel.Sel.Name != "Error" && sel.Sel.Name != "Errorf") {
+		return nil, false
+	}
+	recv, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return nil, false
+	}
+	// The receiver must be a variable of a testing type, not a package such
+	// as slog or something else with an Error method.
+	if r.file.Info == nil {
+		return nil, false
+	}
+	v, ok := r.file.Info.Uses[recv].(*types.Var)
+	if !ok || !isTestingType(v.Type()) {
+		return nil, false
+	}
+	return call, true
+}
+
+func isTestingType(t types.Type) bool {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != "testing" {
+		return false
+	}
+	switch named.Obj().Name() {
+	case "T", "B", "F", "TB":
+		return true
+	}
+	return false
+}
+
+// match returns the assertion equivalent to failing when cond is true.
+func (r *rewriter) match(cond ast.Expr, report *ast.CallExpr) (assertion, bool) {
+	cond = ast.Unparen(cond)
+	switch c := cond.(type) {
+	case *ast.BinaryExpr:
+		if c.Op != token.NEQ && c.Op != token.EQL {
+			return assertion{}, false
+		}
+		return r.matchCompare(c, report)
+	case *ast.UnaryExpr:
+		if c.Op != token.NOT {
+			return assertion{}, false
+		}
+		if name, args, ok := r.matchFunc(c.X, report); ok {
+			return assertion{name, args}, true
+		}
+		if r.isBool(c.X) {
+			return assertion{"True", []ast.Expr{c.X}}, true
+		}
+	case *ast.CallExpr:
+		if name, args, ok := r.matchFunc(c, report); ok {
+			return assertion{negate[name], args}, true
+		}
+	}
+	return assertion{}, false
+}
+
+var negate = map[string]string{
+	"ContainsString": "NotContainsString",
+	"EqualSlice":     "NotEqualSlice",
+	"EqualMap":       "NotEqualMap",
+}
+
+// matchFunc matches strings.Contains, slices.Equal and maps.Equal calls.
+func (r *rewriter) matchFunc(e ast.Expr, report *ast.CallExpr) (string, []ast.Expr, bool) {
+	call, ok := ast.Unparen(e).(*ast.CallExpr)
+	if !ok || len(call.Args) != 2 || call.Ellipsis.IsValid() {
+		return "", nil, false
+	}
+	pkg, fn, ok := r.qualifiedName(call.Fun)
+	if !ok {
+		return "", nil, false
+	}
+	switch pkg + "." + fn {
+	case "strings.Contains":
+		return "ContainsString", call.Args, true
+	case "slices.Equal":
+		expected, actual := r.order(call.Args[0], call.Args[1], report)
+		return "EqualSlice", []ast.Expr{expected, actual}, true
+	case "maps.Equal":
+		expected, actual := r.order(call.Args[0], call.Args[1], report)
+		return "EqualMap", []ast.Expr{expected, actual}, true
+	}
+	return "", nil, false
+}
+
+// qualifiedName returns the import path and name of a package-level function.
+func (r *rewriter) qualifiedName(fun ast.Expr) (string, string, bool) {
+	sel, ok := fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", "", false
+	}
+	id, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return "", "", false
+	}
+	for _, path := range []string{"strings", "slices", "maps"} {
+		if r.file.ImportName(path) == id.Name {
+			return path, sel.Sel.Name, true
+		}
+	}
+	return "", "", false
+}
+
+// matchCompare matches comparisons with ==, != and len.
+func (r *rewriter) matchCompare(c *ast.BinaryExpr, report *ast.CallExpr) (assertion, bool) {
+	x, y := c.X, c.Y
+	if isIdent(x, "nil") {
+		x, y = y, x
+	}
+	if isIdent(y, "nil") {
+		isErr := r.isError(x)
+		switch {
+		case c.Op == token.NEQ && isErr:
+			return assertion{"NoError", []ast.Expr{x}}, true
+		case c.Op == token.NEQ:
+			return assertion{"Nil", []ast.Expr{x}}, true
+		case isErr:
+			return assertion{"Error", []ast.Expr{x}}, true
+		default:
+			return assertion{"NotNil", []ast.Expr{x}}, true
+		}
+	}
+
+	if _, ok := lenArg(y); ok {
+		x, y = y, x
+	}
+	if arg, ok := lenArg(x); ok {
+		if c.Op == token.EQL {
+			if r.isZero(y) {
+				return assertion{"NotEmpty", []ast.Expr{arg}}, true
+			}
+			return assertion{}, false
+		}
+		return assertion{"Len", []ast.Expr{arg, y}}, true
+	}
+
+	if !r.compatible(x, y) {
+		return assertion{}, false
+	}
+	expected, actual := r.order(x, y, report)
+	if c.Op == token.EQL {
+		return assertion{"NotEqual", []ast.Expr{expected, actual}}, true
+	}
+	return assertion{"Equal", []ast.Expr{expected, actual}}, true
+}
+
+// lenArg returns the argument of a call to the len builtin.
+func lenArg(e ast.Expr) (ast.Expr, bool) {
+	call, ok := ast.Unparen(e).(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 || !isIdent(call.Fun, "len") {
+		return nil, false
+	}
+	return call.Args[0], true
+}
+
+// compatible reports whether x and y can be passed to the same type
+// parameter of Equal.
+func (r *rewriter) compatible(x, y ast.Expr) bool {
+	tx, ty := r.file.TypeOf(x), r.file.TypeOf(y)
+	if tx == nil || ty == nil {
+		return true
+	}
+	if isUntyped(tx) || isUntyped(ty) {
+		return true
+	}
+	return types.Identical(tx, ty)
+}
+
+func isUntyped(t types.Type) bool {
+	b, ok := t.(*types.Basic)
+	return ok && b.Info()&types.IsUntyped != 0
+}
+
+var wantWords = regexp.MustCompile(`(?i)\b(want|wanted|expected|expect|exp)\b`)
+
+// order decides which of x and y is the expected value. The failure message
+// is the best guide, then literals and names, and finally the common
+// got != want convention.
+func (r *rewriter) order(x, y ast.Expr, report *ast.CallExpr) (expected, actual ast.Expr) {
+	if roles := r.messageRoles(report); roles != nil {
+		if roles[r.file.Source(x)] == "expected" || roles[r.file.Source(y)] == "actual" {
+			return x, y
+		}
+		if roles[r.file.Source(y)] == "expected" || roles[r.file.Source(x)] == "actual" {
+			return y, x
+		}
+	}
+	if r.isConstant(x) && !r.isConstant(y) {
+		return x, y
+	}
+	if r.isConstant(y) && !r.isConstant(x) {
+		return y, x
+	}
+	if wantWords.MatchString(lastName(x)) && !wantWords.MatchString(lastName(y)) {
+		return x, y
+	}
+	return y, x
+}
+
+var verbRE = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// messageRoles maps the source of Errorf arguments to "expected" or
+// "actual", based on the words preceding their verbs in the format.
+func (r *rewriter) messageRoles(report *ast.CallExpr) map[string]string {
+	if len(report.Args) < 2 {
+		return nil
+	}
+	format, ok := r.stringConstant(report.Args[0])
+	if !ok {
+		return nil
+	}
+	roles := map[string]string{}
+	args := report.Args[1:]
+	prev, argIndex := 0, 0
+	for _, loc := range verbRE.FindAllStringIndex(format, -1) {
+		if format[loc[1]-1] == '%' {
+			continue
+		}
+		if argIndex >= len(args) {
+			break
+		}
+		text := strings.ToLower(format[prev:loc[0]])
+		want := lastIndexAny(text, "want", "expect")
+		got := lastIndexAny(text, "got", "actual", "have")
+		switch {
+		case want > got:
+			roles[r.file.Source(args[argIndex])] = "expected"
+		case got > want:
+			roles[r.file.Source(args[argIndex])] = "actual"
+		}
+		prev = loc[1]
+		argIndex++
+	}
+	return roles
+}
+
+func lastIndexAny(s string, words ...string) int {
+	i := -1
+	for _, w := range words {
+		i = max(i, strings.LastIndex(s, w))
+	}
+	return i
+}
+
+// lastName returns the identifier at the end of a name or selector.
+func lastName(e ast.Expr) string {
+	switch e := ast.Unparen(e).(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	}
+	return ""
+}
+
+// checkMessage returns why dropping the failure message of report would lose
+// information, or "" if it would not.
+func (r *rewriter) checkMessage(report *ast.CallExpr, stmt *ast.IfStmt, a assertion) string {
+	if r.opts.force {
+		return ""
+	}
+	args := report.Args
+	if report.Fun.(*ast.SelectorExpr).Sel.Name == "Errorf" && len(args) > 0 {
+		args = args[1:]
+	}
+	known := map[string]bool{}
+	ast.Inspect(stmt.Cond, func(n ast.Node) bool {
+		if e, ok := n.(ast.Expr); ok {
+			known[r.file.Source(e)] = true
+		}
+		return true
+	})
+	for _, arg := range args {
+		if !known[r.file.Source(arg)] && !r.isConstant(arg) {
+			return fmt.Sprintf("failure message mentions %s; use -force to drop it", r.file.Source(arg))
+		}
+	}
+	return ""
+}
+
+// inlinableInit returns the initializer of stmt if it declares a single
+// variable that is only used in the check's condition and message.
+func (r *rewriter) inlinableInit(stmt *ast.IfStmt, a assertion) (*ast.AssignStmt, bool) {
+	init, ok := stmt.Init.(*ast.AssignStmt)
+	if !ok || init.Tok != token.DEFINE || len(init.Lhs) != 1 || len(init.Rhs) != 1 {
+		return nil, false
+	}
+	id, ok := init.Lhs[0].(*ast.Ident)
+	if !ok || id.Name == "_" {
+		return nil, false
+	}
+	// The variable must appear exactly once among the assertion arguments.
+	uses := 0
+	for _, arg := range a.args {
+		ast.Inspect(arg, func(n ast.Node) bool {
+			if isIdent(n, id.Name) {
+				uses++
+			}
+			return true
+		})
+	}
+	if uses != 1 || !slices.ContainsFunc(a.args, func(e ast.Expr) bool { return isIdent(e, id.Name) }) {
+		return nil, false
+	}
+	return init, true
+}
+
+func (r *rewriter) hasComments(stmt *ast.IfStmt) bool {
+	for _, group := range r.file.AST.Comments {
+		if group.Pos() >= stmt.Pos() && group.End() <= stmt.End() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *rewriter) isError(e ast.Expr) bool {
+	t := r.file.TypeOf(e)
+	if t == nil {
+		name := strings.ToLower(lastName(e))
+		return name == "err" || strings.HasSuffix(name, "err") || strings.HasSuffix(name, "error")
+	}
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+func (r *rewriter) isBool(e ast.Expr) bool {
+	t := r.file.TypeOf(e)
+	if t == nil {
+		return false
+	}
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsBoolean != 0
+}
+
+func (r *rewriter) isConstant(e ast.Expr) bool {
+	if _, ok := ast.Unparen(e).(*ast.BasicLit); ok {
+		return true
+	}
+	if r.file.Info == nil {
+		return false
+	}
+	tv, ok := r.file.Info.Types[e]
+	return ok && tv.Value != nil
+}
+
+func (r *rewriter) isZero(e ast.Expr) bool {
+	if lit, ok := ast.Unparen(e).(*ast.BasicLit); ok {
+		return lit.Value == "0"
+	}
+	if r.file.Info == nil {
+		return false
+	}
+	tv, ok := r.file.Info.Types[e]
+	return ok && tv.Value != nil && constant.Sign(tv.Value) == 0
+}
+
+func (r *rewriter) stringConstant(e ast.Expr) (string, bool) {
+	if lit, ok := ast.Unparen(e).(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		s, err := strconv.Unquote(lit.Value)
+		return s, err == nil
+	}
+	if r.file.Info == nil {
+		return "", false
+	}
+	tv, ok := r.file.Info.Types[e]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+func (r *rewriter) skip(stmt *ast.IfStmt, reason string) {
+	r.stats.Skipped = append(r.stats.Skipped, problem{Pos: r.file.Position(stmt.Pos()), Message: reason})
+}
+
+func isIdent(n ast.Node, name string) bool {
+	id, ok := n.(*ast.Ident)
+	if !ok {
+		if e, isExpr := n.(ast.Expr); isExpr {
+			id, ok = ast.Unparen(e).(*ast.Ident)
+		}
+	}
+	return ok && id.Name == name
+}
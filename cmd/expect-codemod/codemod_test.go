@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/lumertzg/expect/internal/rewrite"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestRewrite(t *testing.T) {
+	const filename = "testdata/a.go"
+	files, err := rewrite.Load([]string{filename})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var st stats
+	rewriteFile(files[0], options{}, &st)
+	got, err := files[0].Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *update {
+		if err := os.WriteFile(filename+".golden", got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(filename + ".golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("rewritten source does not match golden file:\n%s", got)
+	}
+
+	if st.Rewritten != 12 {
+		t.Errorf("expected 12 rewritten checks, got %d", st.Rewritten)
+	}
+	var skipped []string
+	for _, p := range st.Skipped {
+		skipped = append(skipped, p.Message)
+	}
+	wantSkipped := []string{
+		"if statement has an initializer that cannot be inlined",
+		"failure message mentions tc.name; use -force to drop it",
+		"condition is not a recognised check",
+		"check contains comments",
+	}
+	if strings.Join(skipped, "\n") != strings.Join(wantSkipped, "\n") {
+		t.Errorf("got skipped:\n%s\nwant:\n%s", strings.Join(skipped, "\n"), strings.Join(wantSkipped, "\n"))
+	}
+}
+
+func TestRewriteForce(t *testing.T) {
+	files, err := rewrite.Load([]string{"testdata/a.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st stats
+	rewriteFile(files[0], options{force: true}, &st)
+	if st.Rewritten != 13 {
+		t.Errorf("expected 13 rewritten checks, got %d", st.Rewritten)
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	dir := t.TempDir()
+	name := dir + "/b_test.go"
+	src := "package b\n\nimport \"testing\"\n\nfunc TestB(t *testing.T) {\n\tvar err error\n\tif err != nil {\n\t\tt.Error(err)\n\t}\n}\n"
+	if err := os.WriteFile(name, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var report bytes.Buffer
+	if err := run([]string{dir}, options{}, false, false, &report); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != src {
+		t.Errorf("expected dry run to leave the file unchanged, got:\n%s", got)
+	}
+	if !strings.Contains(report.String(), "rewrote 1 checks, skipped 0; would change 1 files") {
+		t.Errorf("unexpected report:\n%s", report.String())
+	}
+}
@@ -0,0 +1,94 @@
+// Command expect-codemod rewrites hand-written test checks into expect
+// assertions.
+//
+// Usage:
+//
+//	expect-codemod [-w] [-d] [-force] [path ...]
+//
+// Paths are files or directories; a trailing /... includes subdirectories.
+// Without -w the files are left untouched, and -d prints the changes as
+// unified diffs, so expect-codemod -d is a dry run.
+//
+// An if statement whose body is a single t.Error or t.Errorf call is
+// rewritten when its condition is one of:
+//
+//	got != want                  expect.Equal(t, want, got)
+//	got == want                  expect.NotEqual(t, want, got)
+//	err != nil                   expect.NoError(t, err)
+//	err == nil                   expect.Error(t, err)
+//	v != nil, v == nil           expect.Nil(t, v), expect.NotNil(t, v)
+//	len(v) != n                  expect.Len(t, v, n)
+//	len(v) == 0                  expect.NotEmpty(t, v)
+//	!strings.Contains(s, sub)    expect.ContainsString(t, s, sub)
+//	strings.Contains(s, sub)     expect.NotContainsString(t, s, sub)
+//	!slices.Equal(got, want)     expect.EqualSlice(t, want, got)
+//	!maps.Equal(got, want)       expect.EqualMap(t, want, got)
+//	!ok                          expect.True(t, ok)
+//
+// The expected value is identified from the failure message ("got %v, want
+// %v"), then from literals and names such as want or expected. A short
+// variable declaration in the if statement, as in if err := f(); err != nil,
+// is inlined into the assertion.
+//
+// Checks whose failure message mentions other values, such as the name of
+// a table test case, are reported and left alone unless -force is set.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lumertzg/expect/internal/rewrite"
+)
+
+func main() {
+	write := flag.Bool("w", false, "write result to the source files")
+	showDiff := flag.Bool("d", false, "display diffs of the changes")
+	force := flag.Bool("force", false, "rewrite checks even if their failure message mentions other values")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: expect-codemod [-w] [-d] [-force] [path ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		paths = []string{"./..."}
+	}
+	if err := run(paths, options{force: *force}, *write, *showDiff, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "expect-codemod: %v\n", err)
+		os.Exit(2)
+	}
+}
+
+func run(paths []string, opts options, write, showDiff bool, report io.Writer) error {
+	files, err := rewrite.Load(paths)
+	if err != nil {
+		return err
+	}
+
+	var st stats
+	changed := 0
+	for _, f := range files {
+		rewriteFile(f, opts, &st)
+		ok, err := f.Output(write, showDiff)
+		if err != nil {
+			return err
+		}
+		if ok {
+			changed++
+		}
+	}
+
+	for _, p := range st.Skipped {
+		fmt.Fprintln(report, p)
+	}
+	verb := "would change"
+	if write {
+		verb = "changed"
+	}
+	fmt.Fprintf(report, "rewrote %d checks, skipped %d; %s %d files\n", st.Rewritten, len(st.Skipped), verb, changed)
+	return nil
+}
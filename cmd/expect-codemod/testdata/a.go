@@ -0,0 +1,108 @@
+package a
+
+import (
+	"errors"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func parse(s string) (int, error) {
+	if s == "" {
+		return 0, errors.New("empty")
+	}
+	return len(s), nil
+}
+
+func TestChecks(t *testing.T) {
+	got, err := parse("abc")
+	want := 3
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got != want {
+		t.Errorf("got %d, want %d", got, want)
+	}
+	if want != got {
+		t.Errorf("expected %d, got %d", want, got)
+	}
+	if got != 3 {
+		t.Error("wrong length")
+	}
+	if _, err := parse(""); err == nil {
+		t.Error("expected an error")
+	}
+	if err := errors.Join(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	values := []string{"a", "b"}
+	if len(values) != 2 {
+		t.Errorf("len(values) = %d, want 2", len(values))
+	}
+	if len(values) == 0 {
+		t.Error("values is empty")
+	}
+	if !slices.Equal(values, []string{"a", "b"}) {
+		t.Errorf("values = %v", values)
+	}
+	if !maps.Equal(map[string]int{}, map[string]int{}) {
+		t.Error("maps differ")
+	}
+
+	s := "hello world"
+	if !strings.Contains(s, "world") {
+		t.Errorf("%q does not contain world", s)
+	}
+	if strings.Contains(s, "golang") {
+		t.Errorf("%q contains golang", s)
+	}
+
+	var p *int
+	if p != nil {
+		t.Error("p is not nil")
+	}
+	ok := got > 0
+	if !ok {
+		t.Error("not ok")
+	}
+}
+
+func TestSkipped(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want int
+	}{{"one", "a", 1}}
+	for _, tc := range tests {
+		got, _ := parse(tc.in)
+		if got != tc.want {
+			t.Errorf("%s: got %d, want %d", tc.name, got, tc.want)
+		}
+		if got < 0 {
+			t.Errorf("negative")
+		}
+		if got != tc.want {
+			// Explain.
+			t.Errorf("got %d", got)
+		}
+	}
+}
+
+func TestNotTesting(t *testing.T) {
+	_, err := parse("")
+	// Error methods of packages and other types are not reports.
+	if err != nil {
+		slog.Error("failed", "err", err)
+	}
+	var log logger
+	if err != nil {
+		log.Error("failed")
+	}
+}
+
+type logger struct{}
+
+func (logger) Error(msg string) {}
@@ -0,0 +1,87 @@
+// Command expect-migrate rewrites testify assertions into expect assertions.
+//
+// Usage:
+//
+//	expect-migrate [-w] [-d] [path ...]
+//
+// Paths are files or directories; a trailing /... includes subdirectories.
+// Without -w the files are left untouched, and -d prints the changes as
+// unified diffs.
+//
+// Calls to the assert and require packages are translated when expect has
+// an equivalent, such as assert.Equal to expect.Equal, and assert.Contains to
+// expect.ContainsString, ContainsSlice or ContainsMapKey depending on the
+// type of the container. testify compares values deeply, so assert.Equal
+// of pointers, interfaces and values holding them becomes expect.DeepEqual,
+// and assert.Equal of values whose types are unknown is left in place.
+// Message arguments are dropped. Calls that cannot be translated are left
+// in place and reported, and the testify imports are only removed once
+// nothing refers to them.
+//
+// expect assertions never stop the test, so translated require calls no
+// longer do either; each is reported, and the summary counts them.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lumertzg/expect/internal/rewrite"
+)
+
+func main() {
+	write := flag.Bool("w", false, "write result to the source files")
+	showDiff := flag.Bool("d", false, "display diffs instead of rewriting files")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: expect-migrate [-w] [-d] [path ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		paths = []string{"./..."}
+	}
+	if err := run(paths, *write, *showDiff, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "expect-migrate: %v\n", err)
+		os.Exit(2)
+	}
+}
+
+func run(paths []string, write, showDiff bool, report io.Writer) error {
+	files, err := rewrite.Load(paths)
+	if err != nil {
+		return err
+	}
+
+	var st stats
+	changed := 0
+	for _, f := range files {
+		migrate(f, &st)
+		ok, err := f.Output(write, showDiff)
+		if err != nil {
+			return err
+		}
+		if ok {
+			changed++
+		}
+	}
+
+	for _, p := range st.Notes {
+		fmt.Fprintln(report, p)
+	}
+	for _, p := range st.Skipped {
+		fmt.Fprintln(report, p)
+	}
+	verb := "would change"
+	if write {
+		verb = "changed"
+	}
+	fmt.Fprintf(report, "translated %d calls, %d left untranslated; %s %d files\n", st.Translated, len(st.Skipped), verb, changed)
+	if st.Require > 0 {
+		fmt.Fprintf(report, "%d translated require calls no longer stop the test on failure\n", st.Require)
+	}
+	return nil
+}
@@ -0,0 +1,437 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/lumertzg/expect/internal/rewrite"
+)
+
+const (
+	assertPath  = "github.com/stretchr/testify/assert"
+	requirePath = "github.com/stretchr/testify/require"
+)
+
+// A problem is a testify call that could not be translated, or a note about
+// one that was.
+type problem struct {
+	Pos     token.Position
+	Message string
+}
+
+func (p problem) String() string {
+	return fmt.Sprintf("%s: %s", p.Pos, p.Message)
+}
+
+// stats counts the outcome of migrating a set of files.
+type stats struct {
+	Translated int
+	Require    int // translated calls that were fatal
+	Skipped    []problem
+	Notes      []problem
+}
+
+// translator rewrites one testify call, given its arguments after the
+// TestingT. It returns the expect function and arguments to call, or a
+// reason why the call cannot be translated.
+type translator func(m *migrator, args []ast.Expr) (name string, out []string, reason string)
+
+// rule describes how to translate a testify function.
+type rule struct {
+	nargs     int // arguments after the TestingT, excluding messages
+	translate translator
+}
+
+// same translates a call to the expect function of the same name with the
+// same arguments.
+func same(name string) translator {
+	return func(m *migrator, args []ast.Expr) (string, []string, string) {
+		return name, m.sources(args), ""
+	}
+}
+
+// ordered translates ordering assertions, which expect restricts to
+// cmp.Ordered types.
+func ordered(name string) translator {
+	return func(m *migrator, args []ast.Expr) (string, []string, string) {
+		for _, arg := range args {
+			if t := m.file.TypeOf(arg); t != nil && !isOrdered(t) {
+				return "", nil, fmt.Sprintf("%s is not an ordered type", typeString(t))
+			}
+		}
+		return name, m.sources(args), ""
+	}
+}
+
+// sorted translates sorting assertions, which expect restricts to slices of
+// cmp.Ordered types.
+func sorted(name string) translator {
+	return func(m *migrator, args []ast.Expr) (string, []string, string) {
+		if t := m.file.TypeOf(args[0]); t != nil {
+			if s, ok := t.Underlying().(*types.Slice); !ok || !isOrdered(s.Elem()) {
+				return "", nil, fmt.Sprintf("%s is not a slice of an ordered type", typeString(t))
+			}
+		}
+		return name, m.sources(args), ""
+	}
+}
+
+var rules = map[string]rule{
+	"Equal":           {2, equal("Equal", "EqualSlice", "EqualMap", "DeepEqual", nilCheck("Nil", "NoError"))},
+	"NotEqual":        {2, equal("NotEqual", "NotEqualSlice", "NotEqualMap", "NotDeepEqual", nilCheck("NotNil", "Error"))},
+	"True":            {1, same("True")},
+	"False":           {1, same("False")},
+	"Nil":             {1, nilCheck("Nil", "NoError")},
+	"NotNil":          {1, nilCheck("NotNil", "Error")},
+	"NoError":         {1, same("NoError")},
+	"Error":           {1, same("Error")},
+	"ErrorIs":         {2, same("ErrorIs")},
+	"NotErrorIs":      {2, same("NotErrorIs")},
+	"ErrorAs":         {2, same("ErrorAs")},
+	"EqualError":      {2, same("EqualError")},
+	"ErrorContains":   {2, same("ErrorContains")},
+	"Contains":        {2, contains("ContainsString", "ContainsSlice", "ContainsMapKey")},
+	"NotContains":     {2, contains("NotContainsString", "NotContainsSlice", "NotContainsMapKey")},
+	"Len":             {2, same("Len")},
+	"Empty":           {1, same("Empty")},
+	"NotEmpty":        {1, same("NotEmpty")},
+	"Zero":            {1, same("Zero")},
+	"NotZero":         {1, same("NotZero")},
+	"Same":            {2, same("Same")},
+	"NotSame":         {2, same("NotSame")},
+	"Greater":         {2, ordered("Greater")},
+	"GreaterOrEqual":  {2, ordered("GreaterOrEqual")},
+	"Less":            {2, ordered("Less")},
+	"LessOrEqual":     {2, ordered("LessOrEqual")},
+	"IsIncreasing":    {1, sorted("IsStrictlyIncreasing")},
+	"IsDecreasing":    {1, sorted("IsStrictlyDecreasing")},
+	"IsNonDecreasing": {1, sorted("IsSorted")},
+}
+
+// equal translates Equal and NotEqual, choosing the expect function that
+// matches the type of the compared values. testify compares deeply, so
+// values that == would compare by identity, or not at all, use the deep
+// assertion. Without the type of either value, the choice cannot be made
+// and the call is left alone.
+func equal(scalar, slice, mapName, deep string, compareNil translator) translator {
+	return func(m *migrator, args []ast.Expr) (string, []string, string) {
+		expected, actual := args[0], args[1]
+		if isNilIdent(expected) {
+			return compareNil(m, args[1:])
+		}
+		if isNilIdent(actual) {
+			return compareNil(m, args[:1])
+		}
+		t := m.typeOf(expected, actual)
+		if t == nil {
+			return "", nil, "cannot determine the type of the compared values"
+		}
+		if comparesDeeply(t) {
+			return deep, m.sources(args), ""
+		}
+
+		switch m.kindOf(expected, actual) {
+		case kindSlice:
+			return slice, m.sources(args), ""
+		case kindMap:
+			return mapName, m.sources(args), ""
+		}
+		return scalar, m.sources(args), ""
+	}
+}
+
+// nilCheck translates Nil and NotNil, using the error assertions for errors.
+func nilCheck(name, errName string) translator {
+	return func(m *migrator, args []ast.Expr) (string, []string, string) {
+		if t := m.file.TypeOf(args[0]); t != nil && types.Identical(t, types.Universe.Lookup("error").Type()) {
+			return errName, m.sources(args), ""
+		}
+		return name, m.sources(args), ""
+	}
+}
+
+// contains translates Contains and NotContains, which testify applies to
+// strings, slices and map keys alike.
+func contains(str, slice, mapName string) translator {
+	return func(m *migrator, args []ast.Expr) (string, []string, string) {
+		if t := m.file.TypeOf(args[0]); t != nil && comparesDeeply(t) {
+			return "", nil, fmt.Sprintf("testify compares the elements of %s deeply", typeString(t))
+		}
+		switch m.kindOf(args[0]) {
+		case kindString:
+			return str, m.sources(args), ""
+		case kindSlice:
+			return slice, m.sources(args), ""
+		case kindMap:
+			return mapName, m.sources(args), ""
+		case kindUnknown:
+			return "", nil, "cannot determine the type of the container"
+		}
+		return "", nil, "container is not a string, slice or map"
+	}
+}
+
+type kind int
+
+const (
+	kindUnknown kind = iota
+	kindString
+	kindSlice
+	kindMap
+	kindOther
+)
+
+// kindOf classifies the first expression of exprs whose type is known.
+func (m *migrator) kindOf(exprs ...ast.Expr) kind {
+	for _, e := range exprs {
+		t := m.file.TypeOf(e)
+		if t == nil {
+			if k := literalKind(e); k != kindUnknown {
+				return k
+			}
+			continue
+		}
+		if b, ok := t.Underlying().(*types.Basic); ok && b.Info()&types.IsString != 0 {
+			return kindString
+		}
+		switch t.Underlying().(type) {
+		case *types.Slice:
+			return kindSlice
+		case *types.Map:
+			return kindMap
+		}
+		return kindOther
+	}
+	return kindUnknown
+}
+
+// typeOf returns the type of the first expression of exprs whose type is
+// known, or nil.
+func (m *migrator) typeOf(exprs ...ast.Expr) types.Type {
+	for _, e := range exprs {
+		if t := m.file.TypeOf(e); t != nil {
+			return t
+		}
+	}
+	return nil
+}
+
+// comparesDeeply reports whether values of t must be compared deeply to
+// match testify: whether they, or the elements of a slice or map, hold
+// pointers, interfaces, functions, slices or maps.
+func comparesDeeply(t types.Type) bool {
+	switch u := t.Underlying().(type) {
+	case *types.Slice:
+		return holdsReferences(u.Elem())
+	case *types.Map:
+		return holdsReferences(u.Key()) || holdsReferences(u.Elem())
+	}
+	return holdsReferences(t)
+}
+
+func holdsReferences(t types.Type) bool {
+	switch u := t.Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Signature, *types.Slice, *types.Map:
+		return true
+	case *types.Array:
+		return holdsReferences(u.Elem())
+	case *types.Struct:
+		for f := range u.Fields() {
+			if holdsReferences(f.Type()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// literalKind classifies e from its syntax when no type information is
+// available.
+func literalKind(e ast.Expr) kind {
+	switch e := ast.Unparen(e).(type) {
+	case *ast.BasicLit:
+		if e.Kind == token.STRING {
+			return kindString
+		}
+		return kindOther
+	case *ast.CompositeLit:
+		switch typ := e.Type.(type) {
+		case *ast.ArrayType:
+			if typ.Len == nil {
+				return kindSlice
+			}
+		case *ast.MapType:
+			return kindMap
+		}
+	}
+	return kindUnknown
+}
+
+func isOrdered(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsOrdered != 0
+}
+
+func isNilIdent(e ast.Expr) bool {
+	id, ok := ast.Unparen(e).(*ast.Ident)
+	return ok && id.Name == "nil"
+}
+
+func typeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string { return p.Name() })
+}
+
+// migrator rewrites the testify calls of one file.
+type migrator struct {
+	file  *rewrite.File
+	stats *stats
+	// qual is the qualifier, including the dot, for expect functions.
+	qual string
+	// used is set once a call to expect has been written.
+	used bool
+}
+
+// migrate rewrites the testify assert and require calls in file.
+func migrate(file *rewrite.File, st *stats) {
+	pkgs := map[string]string{} // local name -> import path
+	for _, path := range []string{assertPath, requirePath} {
+		if name := file.ImportName(path); name != "" && name != "_" && name != "." {
+			pkgs[name] = path
+		}
+	}
+	if len(pkgs) == 0 {
+		return
+	}
+
+	m := &migrator{file: file, stats: st}
+	expectName := file.ImportName(rewrite.ExpectPath)
+	switch expectName {
+	case "", "_":
+		m.qual = "expect."
+	case ".":
+		m.qual = ""
+	default:
+		m.qual = expectName + "."
+	}
+
+	translated := map[ast.Node]bool{}
+	ast.Inspect(file.AST, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		path, ok := pkgs[pkg.Name]
+		if !ok || (file.Info != nil && file.Info.Uses[pkg] != nil && !isPkgName(file.Info.Uses[pkg])) {
+			return true
+		}
+		if m.call(call, pkg.Name, sel.Sel.Name, path == requirePath) {
+			translated[call] = true
+			return false
+		}
+		return true
+	})
+	if !m.used {
+		return
+	}
+
+	needImport := expectName == "" || expectName == "_"
+	for _, name := range slices.Sorted(maps.Keys(pkgs)) {
+		if file.UsesName(name, translated) {
+			continue
+		}
+		if needImport {
+			file.ReplaceImport(pkgs[name], rewrite.ExpectPath)
+			needImport = false
+			continue
+		}
+		file.RemoveImport(pkgs[name])
+	}
+	if needImport {
+		file.AddImport(rewrite.ExpectPath)
+	}
+}
+
+func isPkgName(obj types.Object) bool {
+	_, ok := obj.(*types.PkgName)
+	return ok
+}
+
+// call translates a single testify call and reports whether it did.
+func (m *migrator) call(call *ast.CallExpr, pkg, fn string, fatal bool) bool {
+	qualified := pkg + "." + fn
+	r, ok := rules[fn]
+	formatted := false
+	if !ok && strings.HasSuffix(fn, "f") {
+		r, ok = rules[strings.TrimSuffix(fn, "f")]
+		formatted = true
+	}
+	if !ok {
+		if fn == "New" {
+			m.skip(call, qualified, "assertion objects are not supported; call the package functions instead")
+		} else {
+			m.skip(call, qualified, "no expect equivalent")
+		}
+		return false
+	}
+	if call.Ellipsis.IsValid() {
+		m.skip(call, qualified, "variadic call")
+		return false
+	}
+	if len(call.Args) < 1+r.nargs {
+		m.skip(call, qualified, "too few arguments")
+		return false
+	}
+
+	name, out, reason := r.translate(m, call.Args[1:1+r.nargs])
+	if reason != "" {
+		m.skip(call, qualified, reason)
+		return false
+	}
+	if len(call.Args) > 1+r.nargs || formatted {
+		m.note(call, qualified+": message arguments dropped")
+	}
+	if fatal {
+		m.note(call, qualified+": translated call no longer stops the test on failure")
+	}
+
+	args := append([]string{m.file.Source(call.Args[0])}, out...)
+	m.file.Replace(call, m.qual+name+"("+strings.Join(args, ", ")+")")
+	m.used = true
+	m.stats.Translated++
+	if fatal {
+		m.stats.Require++
+	}
+	return true
+}
+
+func (m *migrator) sources(args []ast.Expr) []string {
+	out := make([]string, len(args))
+	for i, arg := range args {
+		out[i] = m.file.Source(arg)
+	}
+	return out
+}
+
+func (m *migrator) skip(call *ast.CallExpr, fn, reason string) {
+	m.stats.Skipped = append(m.stats.Skipped, problem{
+		Pos:     m.file.Position(call.Pos()),
+		Message: fmt.Sprintf("cannot translate %s: %s", fn, reason),
+	})
+}
+
+func (m *migrator) note(call *ast.CallExpr, msg string) {
+	m.stats.Notes = append(m.stats.Notes, problem{Pos: m.file.Position(call.Pos()), Message: msg})
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lumertzg/expect/internal/rewrite"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestMigrate(t *testing.T) {
+	const filename = "testdata/a.go"
+	files, err := rewrite.Load([]string{filename})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var st stats
+	migrate(files[0], &st)
+	got, err := files[0].Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *update {
+		if err := os.WriteFile(filename+".golden", got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(filename + ".golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("rewritten source does not match golden file:\n%s", got)
+	}
+
+	if st.Translated != 24 || st.Require != 2 {
+		t.Errorf("expected 24 translated calls, 2 from require, got %d, %d", st.Translated, st.Require)
+	}
+
+	var skipped []string
+	for _, p := range st.Skipped {
+		skipped = append(skipped, p.Message)
+	}
+	for _, want := range []string{
+		"cannot translate assert.ElementsMatch: no expect equivalent",
+		"cannot translate assert.Contains: testify compares the elements of []*a.point deeply",
+		"cannot translate assert.IsIncreasing: []*a.point is not a slice of an ordered type",
+		"cannot translate assert.New: assertion objects are not supported",
+	} {
+		if !containsPrefix(skipped, want) {
+			t.Errorf("expected a problem starting with %q, got %q", want, skipped)
+		}
+	}
+
+	var notes []string
+	for _, p := range st.Notes {
+		notes = append(notes, p.String())
+	}
+	for _, want := range []string{
+		"testdata/a.go:24:2: require.NoError: translated call no longer stops the test on failure",
+		"testdata/a.go:42:2: require.Len: message arguments dropped",
+		"testdata/a.go:42:2: require.Len: translated call no longer stops the test on failure",
+	} {
+		if !slices.Contains(notes, want) {
+			t.Errorf("expected note %q, got %q", want, notes)
+		}
+	}
+	if len(notes) != 5 {
+		t.Errorf("expected 5 notes, got %q", notes)
+	}
+}
+
+func TestMigrateRemovesImports(t *testing.T) {
+	dir := t.TempDir()
+	name := dir + "/b_test.go"
+	src := `package b
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestB(t *testing.T) {
+	assert.True(t, true)
+}
+`
+	if err := os.WriteFile(name, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var report bytes.Buffer
+	if err := run([]string{dir}, true, false, &report); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.ReplaceAll(src, `"github.com/stretchr/testify/assert"`, `"github.com/lumertzg/expect"`)
+	want = strings.ReplaceAll(want, "assert.True", "expect.True")
+	if string(got) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if !strings.Contains(report.String(), "translated 1 calls, 0 left untranslated; changed 1 files") {
+		t.Errorf("unexpected report:\n%s", report.String())
+	}
+}
+
+func TestMigrateUnknownTypes(t *testing.T) {
+	dir := t.TempDir()
+	name := dir + "/c_test.go"
+	src := `package c
+
+import (
+	"testing"
+
+	"example.com/missing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestC(t *testing.T) {
+	assert.Equal(t, missing.Want(), missing.Got())
+}
+`
+	if err := os.WriteFile(name, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var report bytes.Buffer
+	if err := run([]string{dir}, true, false, &report); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != src {
+		t.Errorf("expected file to be left alone, got:\n%s", got)
+	}
+	for _, want := range []string{
+		"cannot translate assert.Equal: cannot determine the type of the compared values",
+		"translated 0 calls, 1 left untranslated",
+	} {
+		if !strings.Contains(report.String(), want) {
+			t.Errorf("expected report to contain %q, got:\n%s", want, report.String())
+		}
+	}
+}
+
+func containsPrefix(list []string, prefix string) bool {
+	for _, s := range list {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package a
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errNotFound = errors.New("not found")
+
+type pair struct{ a, b []int }
+
+type point struct{ x int }
+
+func TestMigrate(t *testing.T) {
+	values := []int{1, 2, 3}
+	lookup := map[string]int{"a": 1}
+	name := "gopher"
+	var err error
+
+	// Comments and formatting around calls are preserved.
+	require.NoError(t, err)
+	assert.Equal(t, 3, len(values)) // trailing comment
+	assert.Equal(t, []int{1, 2, 3}, values)
+	assert.NotEqual(t, map[string]int{}, lookup)
+	assert.Equal(t, nil, err)
+	assert.Equalf(t, "gopher", name, "name of %s", "user")
+	assert.True(t, len(values) > 0, "values must not be empty")
+	assert.Nil(t, err)
+	assert.NotNil(t, values)
+	assert.ErrorIs(t, err, errNotFound)
+	assert.Contains(t, name, "go")
+	assert.Contains(t, values, 2)
+	assert.NotContains(t, lookup, "b")
+	assert.Len(t, values, 3)
+	assert.Empty(t, "")
+	assert.Zero(t, len(name))
+	assert.Greater(t, len(values), 1)
+	assert.IsIncreasing(t, values)
+	require.Len(t, values, 3, "values")
+
+	// testify compares deeply, so these need DeepEqual.
+	p, q := &point{1}, &point{1}
+	var x, y any = []int{1}, []int{1}
+	assert.Equal(t, p, q)
+	assert.Equal(t, x, y)
+	assert.NotEqual(t, pair{}, pair{a: values})
+	assert.Equal(t, []*point{p}, []*point{q})
+	assert.Equal(t, point{1}, point{1})
+
+	// These have no translation.
+	assert.ElementsMatch(t, values, []int{3, 2, 1})
+	assert.Contains(t, []*point{p}, q)
+	assert.IsIncreasing(t, []*point{p, q})
+	a := assert.New(t)
+	a.True(true)
+}
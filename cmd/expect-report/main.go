@@ -0,0 +1,106 @@
+// Command expect-report summarises expect assertion failures found in
+// go test -json output.
+//
+// Usage:
+//
+//	go test -json ./... | expect-report [-format text|markdown|junit] [-o file] [file...]
+//
+// Failures are grouped by assertion and package using the structured test
+// attributes emitted by expect. The junit format describes every test, not
+// only the failed ones, and is meant for CI systems. A package that fails
+// to build, or fails outside its tests as when TestMain exits with an
+// error, is reported as a failed test named "[build failed]" or "TestMain".
+//
+// expect-report exits with status 1 if any test failed, so it can replace
+// go test's own exit status in a pipeline.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	format := flag.String("format", "text", "output `format`: text, markdown or junit")
+	out := flag.String("o", "", "write output to `file` instead of standard output")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: expect-report [flags] [file...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	failed, err := run(*format, *out, flag.Args(), os.Stdin, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "expect-report: %v\n", err)
+		os.Exit(2)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// run renders the report for the given inputs and reports whether any test failed.
+func run(format, out string, files []string, stdin io.Reader, stdout io.Writer) (bool, error) {
+	var render func(io.Writer, *report) error
+	switch format {
+	case "text":
+		render = renderText
+	case "markdown":
+		render = renderMarkdown
+	case "junit":
+		render = renderJUnit
+	default:
+		return false, fmt.Errorf("unknown format %q", format)
+	}
+
+	var in io.Reader = stdin
+	if len(files) > 0 {
+		readers := make([]io.Reader, 0, len(files))
+		for _, name := range files {
+			f, err := os.Open(name)
+			if err != nil {
+				return false, err
+			}
+			defer f.Close()
+			readers = append(readers, f)
+		}
+		in = io.MultiReader(readers...)
+	}
+
+	rep, err := parse(in)
+	if err != nil {
+		return false, err
+	}
+
+	w := stdout
+	if out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			return false, err
+		}
+		defer f.Close()
+		w = f
+		if err := render(w, rep); err != nil {
+			return false, err
+		}
+		return rep.failed(), f.Close()
+	}
+	return rep.failed(), render(w, rep)
+}
+
+// failed reports whether any package or test failed.
+func (r *report) failed() bool {
+	for _, p := range r.Packages {
+		if p.Result == "fail" {
+			return true
+		}
+		for _, tc := range p.Tests {
+			if tc.Result == "fail" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"cmp"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// group is the set of failures of one assertion within one package.
+type group struct {
+	Assertion string
+	Package   string
+	Failures  []*failure
+}
+
+// groupFailures groups failures by assertion and package, ordering groups by
+// descending size and then by name so the output is stable.
+func groupFailures(failures []*failure) []*group {
+	type key struct{ assertion, pkg string }
+	byKey := map[key]*group{}
+	var groups []*group
+	for _, f := range failures {
+		k := key{f.Assertion, f.Package}
+		g, ok := byKey[k]
+		if !ok {
+			g = &group{Assertion: f.Assertion, Package: f.Package}
+			byKey[k] = g
+			groups = append(groups, g)
+		}
+		g.Failures = append(g.Failures, f)
+	}
+	slices.SortStableFunc(groups, func(a, b *group) int {
+		return cmp.Or(
+			cmp.Compare(len(b.Failures), len(a.Failures)),
+			cmp.Compare(a.Assertion, b.Assertion),
+			cmp.Compare(a.Package, b.Package),
+		)
+	})
+	return groups
+}
+
+// assertionTotals counts failures per assertion, ordered like groupFailures.
+func assertionTotals(failures []*failure) []*group {
+	byName := map[string]*group{}
+	var totals []*group
+	for _, f := range failures {
+		g, ok := byName[f.Assertion]
+		if !ok {
+			g = &group{Assertion: f.Assertion}
+			byName[f.Assertion] = g
+			totals = append(totals, g)
+		}
+		g.Failures = append(g.Failures, f)
+	}
+	slices.SortStableFunc(totals, func(a, b *group) int {
+		return cmp.Or(cmp.Compare(len(b.Failures), len(a.Failures)), cmp.Compare(a.Assertion, b.Assertion))
+	})
+	return totals
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return "1 " + word
+	}
+	return strconv.Itoa(n) + " " + word + "s"
+}
+
+func renderText(w io.Writer, rep *report) error {
+	failures := rep.failures()
+	others := rep.otherFailures()
+	if len(failures) == 0 && len(others) == 0 {
+		_, err := fmt.Fprintln(w, "no expect failures")
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", plural(len(failures), "expect failure"))
+	for _, g := range assertionTotals(failures) {
+		fmt.Fprintf(&b, "  %-24s %d\n", g.Assertion, len(g.Failures))
+	}
+	for _, g := range groupFailures(failures) {
+		fmt.Fprintf(&b, "\n%s (%d) in %s\n", g.Assertion, len(g.Failures), g.Package)
+		for _, f := range g.Failures {
+			fmt.Fprintf(&b, "  %s %s: %s\n", f.Test, f.Location, firstLine(f.Message))
+		}
+	}
+	if len(others) > 0 {
+		fmt.Fprintf(&b, "\n%s without expect failures\n", plural(len(others), "failed test"))
+		for _, tc := range others {
+			fmt.Fprintf(&b, "  %s %s\n", tc.Package, tc.Name)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func renderMarkdown(w io.Writer, rep *report) error {
+	failures := rep.failures()
+	others := rep.otherFailures()
+
+	var b strings.Builder
+	b.WriteString("## expect failures\n\n")
+	if len(failures) == 0 && len(others) == 0 {
+		b.WriteString("No failures.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	if len(failures) > 0 {
+		b.WriteString("| Assertion | Failures |\n|---|---:|\n")
+		for _, g := range assertionTotals(failures) {
+			fmt.Fprintf(&b, "| `%s` | %d |\n", g.Assertion, len(g.Failures))
+		}
+	}
+	for _, g := range groupFailures(failures) {
+		fmt.Fprintf(&b, "\n### `%s` in `%s`\n\n", g.Assertion, g.Package)
+		b.WriteString("| Test | Location | Message |\n|---|---|---|\n")
+		for _, f := range g.Failures {
+			fmt.Fprintf(&b, "| `%s` | `%s` | %s |\n", f.Test, f.Location, markdownCell(firstLine(f.Message)))
+		}
+	}
+	if len(others) > 0 {
+		b.WriteString("\n### Other failed tests\n\n")
+		for _, tc := range others {
+			fmt.Fprintf(&b, "- `%s` `%s`\n", tc.Package, tc.Name)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "`", "\\`", "<", "&lt;").Replace(s)
+}
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+func renderJUnit(w io.Writer, rep *report) error {
+	suites := junitSuites{}
+	for _, p := range rep.Packages {
+		suite := junitSuite{Name: p.Name, Time: seconds(p.Elapsed)}
+		for _, tc := range p.Tests {
+			c := junitCase{ClassName: p.Name, Name: tc.Name, Time: seconds(tc.Elapsed)}
+			switch tc.Result {
+			case "fail":
+				suite.Failures++
+				c.Failure = junitFailureOf(tc)
+				c.SystemOut = tc.Output.String()
+			case "skip":
+				suite.Skipped++
+				c.Skipped = &struct{}{}
+			}
+			suite.Tests++
+			suite.Cases = append(suite.Cases, c)
+		}
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// junitFailureOf describes all failures of tc in a single element, since
+// many JUnit consumers only read the first failure of a test case.
+func junitFailureOf(tc *testCase) *junitFailure {
+	if len(tc.Failures) == 0 {
+		return &junitFailure{Message: "test failed", Type: "failure", Body: tc.Output.String()}
+	}
+	var body strings.Builder
+	for _, f := range tc.Failures {
+		if f.Location != "" {
+			body.WriteString(f.Location + ": ")
+		}
+		fmt.Fprintf(&body, "%s: %s\n", f.Assertion, f.Message)
+	}
+	first := tc.Failures[0]
+	return &junitFailure{Message: firstLine(first.Message), Type: first.Assertion, Body: body.String()}
+}
+
+func seconds(elapsed float64) string {
+	return strconv.FormatFloat(elapsed, 'f', 3, 64)
+}
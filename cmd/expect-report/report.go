@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lumertzg/expect"
+)
+
+// event is a single line of go test -json output, as produced by test2json.
+type event struct {
+	Time        time.Time
+	Action      string
+	Package     string
+	ImportPath  string // of build-output events
+	Test        string
+	Output      string
+	OutputType  string
+	Key         string
+	Value       string
+	Elapsed     float64
+	FailedBuild string // import path of the package that failed to build
+}
+
+// failure is a single failed assertion.
+type failure struct {
+	Package   string
+	Test      string
+	Assertion string
+	Location  string
+	Expected  string
+	Actual    string
+	Message   string
+}
+
+// testCase is the outcome of a single test.
+type testCase struct {
+	Package  string
+	Name     string
+	Result   string // "pass", "fail" or "skip"
+	Elapsed  float64
+	Output   strings.Builder
+	Failures []*failure
+
+	// pending is the failure whose message is still being read.
+	pending *failure
+	// inMessage reports whether output is currently part of pending's message.
+	inMessage bool
+	// indent is the indentation testing adds to the continuation lines of
+	// pending's message.
+	indent int
+}
+
+// packageResult is the outcome of a package's tests.
+type packageResult struct {
+	Name    string
+	Result  string
+	Elapsed float64
+	Tests   []*testCase
+	byName  map[string]*testCase
+	// output is the output of the package outside its tests.
+	output strings.Builder
+}
+
+// Names of the test cases standing for packages that failed outside their
+// tests.
+const (
+	buildFailed = "[build failed]"
+	testMain    = "TestMain"
+)
+
+// report is the parsed form of a go test -json stream.
+type report struct {
+	Packages []*packageResult
+	byName   map[string]*packageResult
+	// buildOutput holds the compiler output of each package built, by
+	// import path.
+	buildOutput map[string]*strings.Builder
+}
+
+// locationRE matches the file:line prefix testing adds to failure messages.
+var locationRE = regexp.MustCompile(`^\s*([^\s:]+\.go:\d+): ?(.*)$`)
+
+// parse reads go test -json output from r. Lines that are not JSON events
+// are ignored.
+func parse(r io.Reader) (*report, error) {
+	rep := &report{byName: map[string]*packageResult{}, buildOutput: map[string]*strings.Builder{}}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		var ev event
+		if err := json.Unmarshal(line, &ev); err != nil {
+			continue
+		}
+		rep.add(&ev)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading test output: %w", err)
+	}
+	return rep, nil
+}
+
+func (r *report) pkg(name string) *packageResult {
+	p, ok := r.byName[name]
+	if !ok {
+		p = &packageResult{Name: name, byName: map[string]*testCase{}}
+		r.byName[name] = p
+		r.Packages = append(r.Packages, p)
+	}
+	return p
+}
+
+func (p *packageResult) test(name string) *testCase {
+	tc, ok := p.byName[name]
+	if !ok {
+		tc = &testCase{Package: p.Name, Name: name}
+		p.byName[name] = tc
+		p.Tests = append(p.Tests, tc)
+	}
+	return tc
+}
+
+func (r *report) add(ev *event) {
+	if ev.Action == "build-output" {
+		b, ok := r.buildOutput[ev.ImportPath]
+		if !ok {
+			b = &strings.Builder{}
+			r.buildOutput[ev.ImportPath] = b
+		}
+		b.WriteString(ev.Output)
+		return
+	}
+	if ev.Package == "" {
+		return
+	}
+	p := r.pkg(ev.Package)
+	if ev.Test == "" {
+		switch ev.Action {
+		case "output":
+			p.output.WriteString(ev.Output)
+		case "pass", "fail", "skip":
+			p.Result = ev.Action
+			p.Elapsed = ev.Elapsed
+			if ev.Action == "fail" {
+				r.packageFailed(p, ev.FailedBuild)
+			}
+		}
+		return
+	}
+
+	tc := p.test(ev.Test)
+	switch ev.Action {
+	case "attr":
+		tc.attr(ev.Key, ev.Value)
+	case "output":
+		tc.Output.WriteString(ev.Output)
+		tc.output(ev.Output, ev.OutputType)
+	case "pass", "fail", "skip":
+		tc.Result = ev.Action
+		tc.Elapsed = ev.Elapsed
+		tc.pending = nil
+		tc.inMessage = false
+	}
+}
+
+// packageFailed records a failure of p that none of its tests explains, such
+// as a build failure or a TestMain that exits with an error, as a failed
+// test case so that it is not lost from the report.
+func (r *report) packageFailed(p *packageResult, failedBuild string) {
+	name := testMain
+	var output string
+	if failedBuild != "" {
+		name = buildFailed
+		if b, ok := r.buildOutput[failedBuild]; ok {
+			output = b.String()
+		}
+	} else if slices.ContainsFunc(p.Tests, func(tc *testCase) bool { return tc.Result == "fail" }) {
+		return
+	}
+	tc := p.test(name)
+	tc.Result = "fail"
+	tc.Elapsed = p.Elapsed
+	tc.Output.WriteString(output + p.output.String())
+}
+
+func (tc *testCase) attr(key, value string) {
+	if key == expect.AttrAssertion {
+		tc.pending = &failure{Package: tc.Package, Test: tc.Name, Assertion: value}
+		tc.inMessage = false
+		tc.Failures = append(tc.Failures, tc.pending)
+		return
+	}
+	f := tc.pending
+	if f == nil {
+		return
+	}
+	switch key {
+	case expect.AttrLocation:
+		f.Location = value
+	case expect.AttrExpected:
+		f.Expected = value
+	case expect.AttrActual:
+		f.Actual = value
+	}
+}
+
+func (tc *testCase) output(text, outputType string) {
+	f := tc.pending
+	if f == nil {
+		return
+	}
+	text = strings.TrimRight(text, "\r\n")
+	switch {
+	case !tc.inMessage && isMessageStart(text, outputType):
+		// testing indents continuation lines one level deeper than the
+		// first line of the message.
+		tc.indent = len(text) - len(strings.TrimLeft(text, " ")) + 4
+		if m := locationRE.FindStringSubmatch(text); m != nil {
+			if f.Location == "" {
+				f.Location = m[1]
+			}
+			text = m[2]
+		}
+		f.Message = text
+		tc.inMessage = true
+	case tc.inMessage && isMessageContinuation(text, outputType):
+		f.Message += "\n" + trimIndent(text, tc.indent)
+	default:
+		if tc.inMessage {
+			tc.pending = nil
+			tc.inMessage = false
+		}
+	}
+}
+
+// trimIndent removes up to n leading spaces from text, keeping the
+// indentation that belongs to the message itself.
+func trimIndent(text string, n int) string {
+	i := 0
+	for i < n && i < len(text) && text[i] == ' ' {
+		i++
+	}
+	return text[i:]
+}
+
+// isMessageStart reports whether an output line starts a failure message.
+// Older toolchains do not set OutputType, so fall back to the line shape.
+func isMessageStart(text, outputType string) bool {
+	if outputType != "" {
+		return outputType == "error"
+	}
+	return locationRE.MatchString(text)
+}
+
+func isMessageContinuation(text, outputType string) bool {
+	if outputType != "" {
+		return outputType == "error-continue"
+	}
+	return strings.HasPrefix(text, "        ") && !locationRE.MatchString(text)
+}
+
+// failures returns all assertion failures in input order.
+func (r *report) failures() []*failure {
+	var out []*failure
+	for _, p := range r.Packages {
+		for _, tc := range p.Tests {
+			out = append(out, tc.Failures...)
+		}
+	}
+	return out
+}
+
+// otherFailures returns the failed tests that have no assertion failures
+// attributed to this library.
+func (r *report) otherFailures() []*testCase {
+	var out []*testCase
+	for _, p := range r.Packages {
+		for _, tc := range p.Tests {
+			if tc.Result == "fail" && len(tc.Failures) == 0 && !hasFailedSubtest(p, tc) {
+				out = append(out, tc)
+			}
+		}
+	}
+	return out
+}
+
+// hasFailedSubtest reports whether a failed subtest of tc explains its failure.
+func hasFailedSubtest(p *packageResult, tc *testCase) bool {
+	prefix := tc.Name + "/"
+	for _, sub := range p.Tests {
+		if strings.HasPrefix(sub.Name, prefix) && sub.Result == "fail" {
+			return true
+		}
+	}
+	return false
+}
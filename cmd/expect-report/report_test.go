@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"strings"
+	"testing"
+)
+
+func parseSample(t *testing.T) *report {
+	t.Helper()
+	return parseFile(t, "testdata/sample.json")
+}
+
+func parseFile(t *testing.T, name string) *report {
+	t.Helper()
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rep, err := parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rep
+}
+
+func TestParse(t *testing.T) {
+	rep := parseSample(t)
+
+	failures := rep.failures()
+	if len(failures) != 4 {
+		t.Fatalf("expected 4 failures, got %d", len(failures))
+	}
+
+	f := failures[1]
+	if f.Assertion != "Equal" || f.Test != "TestA" || f.Location != "a_test.go:3" {
+		t.Errorf("unexpected failure %+v", f)
+	}
+	if f.Message != "expected a\nb, got c" {
+		t.Errorf("expected multi-line message, got %q", f.Message)
+	}
+	if f.Expected != `a\nb` || f.Actual != "c" {
+		t.Errorf("expected values a\\nb and c, got %q and %q", f.Expected, f.Actual)
+	}
+
+	others := rep.otherFailures()
+	if len(others) != 1 || others[0].Name != "TestC" {
+		t.Errorf("expected TestC as the only other failure, got %v", others)
+	}
+	if !rep.failed() {
+		t.Error("expected report to be failed")
+	}
+}
+
+func TestParsePackageFailures(t *testing.T) {
+	rep := parseFile(t, "testdata/packages.json")
+	if !rep.failed() {
+		t.Error("expected report to be failed")
+	}
+	others := rep.otherFailures()
+	if len(others) != 2 || others[0].Name != "[build failed]" || others[1].Name != "TestMain" {
+		t.Fatalf("expected the build failure and TestMain as other failures, got %v", others)
+	}
+	if out := others[0].Output.String(); !strings.Contains(out, "undefined: undefined") {
+		t.Errorf("expected build output, got %q", out)
+	}
+	if out := others[1].Output.String(); !strings.Contains(out, "leaked goroutines") {
+		t.Errorf("expected package output, got %q", out)
+	}
+
+	var buf bytes.Buffer
+	if err := renderText(&buf, rep); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"2 failed tests without expect failures",
+		"example.com/bf/broken [build failed]",
+		"example.com/bf/main TestMain",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := renderMarkdown(&buf, rep); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "[build failed]") {
+		t.Errorf("expected build failure in other failed tests, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := renderJUnit(&buf, rep); err != nil {
+		t.Fatal(err)
+	}
+	var suites junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+	if suites.Tests != 3 || suites.Failures != 2 {
+		t.Errorf("expected 3 tests, 2 failures, got %d, %d", suites.Tests, suites.Failures)
+	}
+}
+
+func TestParseIgnoresNonJSON(t *testing.T) {
+	rep, err := parse(strings.NewReader("# build output\n{\"Action\":\"pass\",\"Package\":\"p\"}\nnot json {\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.failed() || len(rep.Packages) != 1 {
+		t.Errorf("expected a single passing package, got %+v", rep.Packages)
+	}
+}
+
+func TestParseWithoutOutputType(t *testing.T) {
+	input := `{"Action":"attr","Package":"p","Test":"TestX","Key":"expect.assertion","Value":"Len"}
+{"Action":"output","Package":"p","Test":"TestX","Output":"    x_test.go:9: expected length 2, got 1\n"}
+{"Action":"output","Package":"p","Test":"TestX","Output":"--- FAIL: TestX (0.00s)\n"}
+{"Action":"fail","Package":"p","Test":"TestX"}
+`
+	rep, err := parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	failures := rep.failures()
+	if len(failures) != 1 {
+		t.Fatalf("expected 1 failure, got %d", len(failures))
+	}
+	if f := failures[0]; f.Location != "x_test.go:9" || f.Message != "expected length 2, got 1" {
+		t.Errorf("unexpected failure %+v", f)
+	}
+}
+
+func TestParseKeepsMessageIndentation(t *testing.T) {
+	input := `{"Action":"attr","Package":"p","Test":"TestX","Key":"expect.assertion","Value":"ErrorIs"}
+{"Action":"output","Package":"p","Test":"TestX","Output":"    x_test.go:9: expected error to match\n","OutputType":"error"}
+{"Action":"output","Package":"p","Test":"TestX","Output":"        join\n","OutputType":"error-continue"}
+{"Action":"output","Package":"p","Test":"TestX","Output":"          wrapped\n","OutputType":"error-continue"}
+{"Action":"fail","Package":"p","Test":"TestX"}
+`
+	rep, err := parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "expected error to match\njoin\n  wrapped"
+	if got := rep.failures()[0].Message; got != want {
+		t.Errorf("expected message %q, got %q", want, got)
+	}
+}
+
+func TestGroupFailures(t *testing.T) {
+	groups := groupFailures(parseSample(t).failures())
+	var names []string
+	for _, g := range groups {
+		names = append(names, g.Assertion)
+	}
+	if got := strings.Join(names, ","); got != "Equal,Len,NoError" {
+		t.Errorf("expected groups Equal,Len,NoError, got %s", got)
+	}
+}
+
+func TestRenderText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := renderText(&buf, parseSample(t)); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"4 expect failures",
+		"Equal (2) in example.com/rep",
+		"TestB/sub a_test.go:4: expected length 2, got 1",
+		"1 failed test without expect failures",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := renderMarkdown(&buf, parseSample(t)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "| `NoError` | 1 |") {
+		t.Errorf("expected assertion table, got:\n%s", buf.String())
+	}
+}
+
+func TestRenderJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := renderJUnit(&buf, parseSample(t)); err != nil {
+		t.Fatal(err)
+	}
+	var suites junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+	if suites.Tests != 6 || suites.Failures != 4 || suites.Skipped != 1 {
+		t.Errorf("expected 6 tests, 4 failures, 1 skipped, got %d, %d, %d", suites.Tests, suites.Failures, suites.Skipped)
+	}
+	c := suites.Suites[0].Cases[0]
+	if c.Failure == nil || c.Failure.Type != "Equal" {
+		t.Errorf("expected Equal failure for %s, got %+v", c.Name, c.Failure)
+	}
+}
+
+func TestRunUnknownFormat(t *testing.T) {
+	if _, err := run("yaml", "", nil, strings.NewReader(""), &bytes.Buffer{}); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
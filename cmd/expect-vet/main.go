@@ -0,0 +1,412 @@
+// Command expect-vet reports common misuse of expect assertions.
+//
+// Usage:
+//
+//	expect-vet [-fix] [packages]
+//	go vet -vettool=$(which expect-vet) [-fix] [packages]
+//
+// Run directly, expect-vet invokes go vet with itself as the vet tool, so it
+// accepts the same package patterns and build flags. With -fix, suggested
+// fixes are applied to the source files. See package expectvet for the list
+// of checks.
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/lumertzg/expect/expectvet"
+	"github.com/lumertzg/expect/internal/diff"
+)
+
+// config is the subset of the configuration go vet passes to vet tools.
+type config struct {
+	ID                        string
+	Compiler                  string
+	Dir                       string
+	ImportPath                string
+	GoFiles                   []string
+	ImportMap                 map[string]string
+	PackageFile               map[string]string
+	VetxOnly                  bool
+	VetxOutput                string
+	FixArchive                string
+	Stdout                    string
+	GoVersion                 string
+	SucceedOnTypecheckFailure bool
+}
+
+var (
+	fix        = flag.Bool("fix", false, "apply suggested fixes")
+	showDiff   = flag.Bool("diff", false, "with -fix, print the fixes as diffs instead of applying them")
+	jsonOutput = flag.Bool("json", false, "print diagnostics and fixes in JSON")
+)
+
+func main() {
+	flag.Var(versionFlag{}, "V", "print version and exit")
+	printFlags := flag.Bool("flags", false, "print analyzer flags in JSON")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: expect-vet [-fix] [packages]\n\n%s\n\nFlags:\n", expectvet.Doc)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *printFlags {
+		printFlagsJSON()
+		return
+	}
+
+	args := flag.Args()
+	if len(args) == 1 && strings.HasSuffix(args[0], ".cfg") {
+		os.Exit(runUnit(args[0]))
+	}
+	os.Exit(runGoVet(args))
+}
+
+// runGoVet runs go vet with this executable as the vet tool.
+func runGoVet(args []string) int {
+	self, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "expect-vet: %v\n", err)
+		return 2
+	}
+	vetArgs := []string{"vet", "-vettool=" + self}
+	flag.Visit(func(f *flag.Flag) {
+		vetArgs = append(vetArgs, "-"+f.Name+"="+f.Value.String())
+	})
+	cmd := exec.Command("go", append(vetArgs, args...)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		if exit, ok := err.(*exec.ExitError); ok {
+			return exit.ExitCode()
+		}
+		fmt.Fprintf(os.Stderr, "expect-vet: %v\n", err)
+		return 2
+	}
+	return 0
+}
+
+// runUnit analyzes the single package described by the vet config file.
+func runUnit(cfgFile string) int {
+	cfg, err := readConfig(cfgFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "expect-vet: %v\n", err)
+		return 2
+	}
+	if cfg.Stdout != "" {
+		f, err := os.Create(cfg.Stdout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "expect-vet: %v\n", err)
+			return 2
+		}
+		defer f.Close()
+		os.Stdout = f
+	}
+	// This analyzer produces no facts, but go vet expects the output file.
+	if cfg.VetxOutput != "" {
+		if err := os.WriteFile(cfg.VetxOutput, nil, 0o666); err != nil {
+			fmt.Fprintf(os.Stderr, "expect-vet: %v\n", err)
+			return 2
+		}
+	}
+	if cfg.VetxOnly {
+		return 0
+	}
+
+	fset := token.NewFileSet()
+	pass, sources, err := load(fset, cfg)
+	if err != nil {
+		if cfg.SucceedOnTypecheckFailure {
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "expect-vet: %v\n", err)
+		return 1
+	}
+
+	var diags []expectvet.Diagnostic
+	pass.Report = func(d expectvet.Diagnostic) { diags = append(diags, d) }
+	if err := expectvet.Run(pass); err != nil {
+		fmt.Fprintf(os.Stderr, "expect-vet: %v\n", err)
+		return 1
+	}
+	slices.SortFunc(diags, func(a, b expectvet.Diagnostic) int { return cmp.Compare(a.Pos, b.Pos) })
+
+	switch {
+	case *fix:
+		if err := applyFixes(fset, cfg, sources, diags); err != nil {
+			fmt.Fprintf(os.Stderr, "expect-vet: %v\n", err)
+			return 1
+		}
+		return 0
+	case *jsonOutput:
+		return printJSON(fset, cfg.ID, diags)
+	}
+
+	for _, d := range diags {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", fset.Position(d.Pos), d.Message)
+	}
+	if len(diags) > 0 {
+		return 1
+	}
+	return 0
+}
+
+func readConfig(name string) (*config, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	cfg := new(config)
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("cannot decode JSON config file %s: %v", name, err)
+	}
+	if len(cfg.GoFiles) == 0 {
+		return nil, fmt.Errorf("package has no files: %s", cfg.ImportPath)
+	}
+	return cfg, nil
+}
+
+// load parses and type-checks the package described by cfg, returning the
+// pass and the source of each file.
+func load(fset *token.FileSet, cfg *config) (*expectvet.Pass, map[string][]byte, error) {
+	sources := map[string][]byte{}
+	var files []*ast.File
+	for _, name := range cfg.GoFiles {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		f, err := parser.ParseFile(fset, name, src, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			return nil, nil, err
+		}
+		sources[name] = src
+		files = append(files, f)
+	}
+
+	compilerImporter := importer.ForCompiler(fset, cfg.Compiler, func(path string) (io.ReadCloser, error) {
+		file, ok := cfg.PackageFile[path]
+		if !ok {
+			return nil, fmt.Errorf("no package file for %q", path)
+		}
+		return os.Open(file)
+	})
+	conf := types.Config{
+		Importer: importerFunc(func(importPath string) (*types.Package, error) {
+			path, ok := cfg.ImportMap[importPath]
+			if !ok {
+				return nil, fmt.Errorf("can't resolve import %q", importPath)
+			}
+			return compilerImporter.Import(path)
+		}),
+		GoVersion: cfg.GoVersion,
+	}
+	info := expectvet.NewTypesInfo()
+	pkg, err := conf.Check(cfg.ImportPath, fset, files, info)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &expectvet.Pass{Fset: fset, Files: files, Pkg: pkg, TypesInfo: info}, sources, nil
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+// applyFixes applies the first suggested fix of each diagnostic. Depending
+// on the flags and cfg, the fixed files are written to the fix archive go vet
+// asked for, printed as diffs, or rewritten in place.
+func applyFixes(fset *token.FileSet, cfg *config, sources map[string][]byte, diags []expectvet.Diagnostic) error {
+	edits := map[string][]expectvet.TextEdit{}
+	for _, d := range diags {
+		if len(d.SuggestedFixes) == 0 {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", fset.Position(d.Pos), d.Message)
+			continue
+		}
+		name := fset.File(d.Pos).Name()
+		edits[name] = append(edits[name], d.SuggestedFixes[0].TextEdits...)
+	}
+
+	fixed := map[string][]byte{}
+	for name, fileEdits := range edits {
+		out, err := expectvet.ApplyEdits(fset, sources[name], fileEdits)
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		fixed[name] = out
+	}
+
+	switch {
+	case *showDiff:
+		for _, name := range slices.Sorted(maps.Keys(fixed)) {
+			os.Stdout.Write(diff.Unified(name+" (old)", name+" (new)", sources[name], fixed[name]))
+		}
+		return nil
+	case cfg.FixArchive != "":
+		return writeArchive(cfg.FixArchive, cfg.ID, fixed)
+	}
+	for name, out := range fixed {
+		// The same file can be analyzed as part of the package and its test
+		// variant; only rewrite it if nobody else already did.
+		current, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(current, sources[name]) {
+			continue
+		}
+		if err := os.WriteFile(name, out, 0o666); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeArchive(name, id string, files map[string][]byte) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	zw.SetComment(id)
+	for _, file := range slices.Sorted(maps.Keys(files)) {
+		w, err := zw.Create(file)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(files[file]); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func printFlagsJSON() {
+	type jsonFlag struct {
+		Name  string
+		Bool  bool
+		Usage string
+	}
+	var flags []jsonFlag
+	flag.VisitAll(func(f *flag.Flag) {
+		if f.Name == "V" || f.Name == "flags" {
+			return
+		}
+		b, ok := f.Value.(interface{ IsBoolFlag() bool })
+		flags = append(flags, jsonFlag{Name: f.Name, Bool: ok && b.IsBoolFlag(), Usage: f.Usage})
+	})
+	data, err := json.MarshalIndent(flags, "", "\t")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "expect-vet: %v\n", err)
+		os.Exit(2)
+	}
+	os.Stdout.Write(data)
+}
+
+// versionFlag implements -V=full, which go vet uses to compute a cache key
+// for the tool.
+type versionFlag struct{}
+
+func (versionFlag) IsBoolFlag() bool { return true }
+func (versionFlag) Get() any         { return nil }
+func (versionFlag) String() string   { return "" }
+
+func (versionFlag) Set(s string) error {
+	if s != "full" {
+		return fmt.Errorf("unsupported flag value: -V=%s", s)
+	}
+	progname, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(progname)
+	if err != nil {
+		return err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	f.Close()
+	fmt.Printf("%s version devel comments-go-here buildID=%02x\n", progname, string(h.Sum(nil)))
+	os.Exit(0)
+	return nil
+}
+
+// printJSON prints diags in the format go vet -json uses, keyed by package
+// ID and analyzer name.
+func printJSON(fset *token.FileSet, id string, diags []expectvet.Diagnostic) int {
+	type jsonEdit struct {
+		Filename string `json:"filename"`
+		Start    int    `json:"start"`
+		End      int    `json:"end"`
+		New      string `json:"new"`
+	}
+	type jsonFix struct {
+		Message string     `json:"message"`
+		Edits   []jsonEdit `json:"edits"`
+	}
+	type jsonDiagnostic struct {
+		Category       string    `json:"category,omitempty"`
+		Posn           string    `json:"posn"`
+		End            string    `json:"end"`
+		Message        string    `json:"message"`
+		SuggestedFixes []jsonFix `json:"suggested_fixes,omitempty"`
+	}
+
+	out := make([]jsonDiagnostic, 0, len(diags))
+	for _, d := range diags {
+		jd := jsonDiagnostic{
+			Category: d.Category,
+			Posn:     fset.Position(d.Pos).String(),
+			End:      fset.Position(d.End).String(),
+			Message:  d.Message,
+		}
+		for _, f := range d.SuggestedFixes {
+			jf := jsonFix{Message: f.Message}
+			for _, e := range f.TextEdits {
+				start := fset.Position(e.Pos)
+				jf.Edits = append(jf.Edits, jsonEdit{
+					Filename: start.Filename,
+					Start:    start.Offset,
+					End:      fset.Position(e.End).Offset,
+					New:      string(e.NewText),
+				})
+			}
+			jd.SuggestedFixes = append(jd.SuggestedFixes, jf)
+		}
+		out = append(out, jd)
+	}
+
+	tree := map[string]map[string]any{}
+	if len(out) > 0 {
+		tree[id] = map[string]any{expectvet.Name: out}
+	}
+	data, err := json.MarshalIndent(tree, "", "\t")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "expect-vet: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stdout, "%s\n", data)
+	return 0
+}
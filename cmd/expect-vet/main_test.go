@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// buildTool builds expect-vet into a temporary directory.
+func buildTool(t *testing.T) string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping go vet integration test in short mode")
+	}
+	tool := filepath.Join(t.TempDir(), "expect-vet")
+	out, err := exec.Command("go", "build", "-o", tool, ".").CombinedOutput()
+	if err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	return tool
+}
+
+func TestVetTool(t *testing.T) {
+	tool := buildTool(t)
+
+	cmd := exec.Command("go", "vet", "-vettool="+tool, "./testdata/a")
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected go vet to fail, got:\n%s", out)
+	}
+	for _, want := range []string{
+		"a_test.go:12:2: Equal: literal 3 passed as the actual value",
+		"a_test.go:13:2: use NoError instead of Nil to check errors",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestStandaloneDiff(t *testing.T) {
+	tool := buildTool(t)
+
+	out, err := exec.Command(tool, "-fix", "-diff", "./testdata/a").CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected diffs to be reported as failure, got:\n%s", out)
+	}
+	for _, want := range []string{
+		"-\texpect.Equal(t, got, 3)\n",
+		"+\texpect.Equal(t, 3, got)\n",
+		"-\texpect.Nil(t, err)\n",
+		"+\texpect.NoError(t, err)\n",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	// -diff must leave the files untouched.
+	src, err := os.ReadFile("testdata/a/a_test.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(src), "expect.Nil(t, err)") {
+		t.Error("expected source to be unchanged")
+	}
+}
+
+func TestFlagsJSON(t *testing.T) {
+	tool := buildTool(t)
+
+	out, err := exec.Command(tool, "-flags").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"Name": "fix"`, `"Name": "json"`, `"Name": "diff"`} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected -flags output to contain %s, got:\n%s", want, out)
+		}
+	}
+}
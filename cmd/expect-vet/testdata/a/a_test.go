@@ -0,0 +1,14 @@
+package a
+
+import (
+	"testing"
+
+	"github.com/lumertzg/expect"
+)
+
+func TestA(t *testing.T) {
+	var err error
+	got := len("abc")
+	expect.Equal(t, got, 3)
+	expect.Nil(t, err)
+}
@@ -0,0 +1,90 @@
+package expect
+
+import (
+	"runtime"
+	"time"
+)
+
+// CompletesWithin asserts that fn returns within d, and reports whether it
+// did. fn runs in its own goroutine; on timeout the failure includes the
+// stacks of all goroutines, and fn is left running.
+//
+//	expect.CompletesWithin(t, time.Second, func() { pool.Close() })
+func CompletesWithin(t T, d time.Duration, fn func()) bool {
+	t.Helper()
+	done := run(fn)
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case r := <-done:
+		if r.panicked {
+			errorf(t, "CompletesWithin", "expected function to complete within %v, but it panicked: %v", d, r.value)
+			return false
+		}
+		return true
+	case <-timer.C:
+		errorf(t, "CompletesWithin", "expected function to complete within %v, but it is still running\n%s", d, goroutineStacks())
+		return false
+	}
+}
+
+// Blocks asserts that fn does not return within d, as when it waits on a
+// lock or channel that is never released. fn runs in its own goroutine and
+// is left running.
+func Blocks(t T, d time.Duration, fn func()) {
+	t.Helper()
+	done := run(fn)
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case r := <-done:
+		if r.panicked {
+			errorf(t, "Blocks", "expected function to block for %v, but it panicked: %v", d, r.value)
+			return
+		}
+		errorf(t, "Blocks", "expected function to block for %v, but it returned", d)
+	case <-timer.C:
+	}
+}
+
+// outcome is how a function run by run ended.
+type outcome struct {
+	panicked bool
+	value    any
+}
+
+// run calls fn in a new goroutine and delivers its outcome on the returned
+// channel, which is buffered so the goroutine can always exit.
+func run(fn func()) <-chan outcome {
+	done := make(chan outcome, 1)
+	go func() {
+		r := outcome{panicked: true}
+		defer func() {
+			if r.panicked {
+				// recover returns nil when fn called runtime.Goexit, as
+				// t.FailNow does.
+				if r.value = recover(); r.value == nil {
+					r.value = "runtime.Goexit"
+				}
+			}
+			done <- r
+		}()
+		fn()
+		r.panicked = false
+	}()
+	return done
+}
+
+// goroutineStacks returns the formatted stacks of all goroutines.
+func goroutineStacks() string {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
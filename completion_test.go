@@ -0,0 +1,82 @@
+package expect
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCompletesWithin(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		if !CompletesWithin(m, longWait, func() {}) || m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		release := make(chan struct{})
+		defer close(release)
+		if CompletesWithin(m, shortWait, func() { <-release }) || !m.failed {
+			t.Error("expected fail")
+		}
+		if !strings.Contains(m.message, "goroutine ") || !strings.Contains(m.message, "TestCompletesWithin") {
+			t.Errorf("expected goroutine stacks, got:\n%s", m.message)
+		}
+	})
+
+	t.Run("fail panic", func(t *testing.T) {
+		m := &mockT{}
+		if CompletesWithin(m, longWait, func() { panic("boom") }) || !m.failed {
+			t.Error("expected fail")
+		}
+		if want := "expected function to complete within 1s, but it panicked: boom"; m.message != want {
+			t.Errorf("got %q, want %q", m.message, want)
+		}
+	})
+
+	t.Run("fail goexit", func(t *testing.T) {
+		m := &mockT{}
+		if CompletesWithin(m, longWait, runtime.Goexit) || !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestBlocks(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		var mu sync.Mutex
+		mu.Lock()
+		defer mu.Unlock()
+		Blocks(m, shortWait, mu.Lock)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		Blocks(m, longWait, func() {})
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail panic", func(t *testing.T) {
+		m := &mockT{}
+		Blocks(m, longWait, func() { panic("boom") })
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestGoroutineStacks(t *testing.T) {
+	stacks := goroutineStacks()
+	if !strings.HasPrefix(stacks, "goroutine ") || !strings.Contains(stacks, "TestGoroutineStacks") {
+		t.Errorf("unexpected stacks:\n%s", stacks)
+	}
+}
@@ -0,0 +1,217 @@
+package expect
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+)
+
+// fatalNote marks failures after which a goroutine other than the test
+// goroutine was stopped.
+const fatalNote = " (fatal: goroutine stopped)"
+
+type failNowT interface {
+	FailNow()
+}
+
+// ConcurrentT is a T that assertions can use from any goroutine. Failures
+// reported on the test goroutine, the one that called [Concurrent], are
+// passed to the wrapped T directly. Failures reported from other goroutines
+// are buffered with the goroutine and location they came from, and
+// forwarded to the wrapped T on the test goroutine: before its next
+// failure, on [ConcurrentT.Flush], and when the test ends.
+//
+// Once the test has ended, the wrapped T can no longer be used, and
+// *testing.T panics if it is. Later failures are instead written to
+// standard error as late failures.
+//
+//	ct := expect.Concurrent(t)
+//	var wg sync.WaitGroup
+//	for _, addr := range addrs {
+//		wg.Go(func() {
+//			resp, err := client.Get(addr)
+//			expect.NoError(ct, err)
+//			...
+//		})
+//	}
+//	wg.Wait()
+//	ct.Flush()
+type ConcurrentT struct {
+	t    T
+	test uint64
+	late io.Writer
+
+	mu      sync.Mutex
+	pending []failure
+	attrs   map[uint64][]string
+	ended   bool
+}
+
+// failure is a failure buffered from a goroutine other than the test
+// goroutine.
+type failure struct {
+	goroutine uint64
+	location  string
+	message   string
+	attrs     []string
+}
+
+func (f failure) String() string {
+	return fmt.Sprintf("goroutine %d at %s: %s", f.goroutine, f.location, f.message)
+}
+
+// Concurrent wraps t for use from multiple goroutines. It must be called on
+// the test goroutine. If t has a Cleanup method, as *testing.T does, the
+// wrapper ends with the test; otherwise [ConcurrentT.End] must be called
+// when the test ends.
+func Concurrent(t T) *ConcurrentT {
+	t.Helper()
+	c := &ConcurrentT{t: t, test: goroutineID(), late: os.Stderr}
+	if ct, ok := t.(cleanupT); ok {
+		ct.Cleanup(c.End)
+	}
+	return c
+}
+
+// Helper marks the calling function as a test helper function.
+func (c *ConcurrentT) Helper() {
+	c.t.Helper()
+}
+
+// Errorf reports a failure. It is safe to call from any goroutine, before
+// or after the test ends.
+func (c *ConcurrentT) Errorf(format string, args ...any) {
+	c.t.Helper()
+	c.report(fmt.Sprintf(format, args...))
+}
+
+// Fatalf is equivalent to Errorf followed by FailNow.
+func (c *ConcurrentT) Fatalf(format string, args ...any) {
+	c.t.Helper()
+	message := fmt.Sprintf(format, args...)
+	if goroutineID() != c.test {
+		c.report(message + fatalNote)
+		runtime.Goexit()
+	}
+	c.report(message)
+	c.FailNow()
+}
+
+// FailNow stops the calling goroutine. On the test goroutine, it flushes
+// the buffered failures and calls the FailNow method of the wrapped T, if
+// it has one. Elsewhere, where calling *testing.T's FailNow is not allowed,
+// it marks the goroutine's last failure as fatal and calls runtime.Goexit,
+// which runs the goroutine's deferred calls; the test goroutine carries on.
+func (c *ConcurrentT) FailNow() {
+	c.t.Helper()
+	if id := goroutineID(); id != c.test {
+		c.markFatal(id)
+		runtime.Goexit()
+	}
+	c.Flush()
+	if ft, ok := c.t.(failNowT); ok {
+		ft.FailNow()
+	}
+}
+
+// Attr records a test attribute, as *testing.T's Attr method does. Off the
+// test goroutine, it is forwarded with the goroutine's next failure.
+func (c *ConcurrentT) Attr(key, value string) {
+	id := goroutineID()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if id != c.test || c.ended {
+		if c.attrs == nil {
+			c.attrs = map[uint64][]string{}
+		}
+		c.attrs[id] = append(c.attrs[id], key, value)
+		return
+	}
+	c.flushLocked()
+	if at, ok := c.t.(attrT); ok {
+		at.Attr(key, value)
+	}
+}
+
+// Flush reports the failures buffered from other goroutines to the wrapped
+// T. It must be called on the test goroutine; elsewhere, it does nothing.
+func (c *ConcurrentT) Flush() {
+	c.t.Helper()
+	if goroutineID() != c.test {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.flushLocked()
+}
+
+// End flushes the buffered failures and marks the end of the test, after
+// which failures are reported as late. It is called at cleanup when the
+// wrapped T has a Cleanup method.
+func (c *ConcurrentT) End() {
+	c.t.Helper()
+	c.Flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ended = true
+}
+
+func (c *ConcurrentT) report(message string) {
+	c.t.Helper()
+	id := goroutineID()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if id == c.test && !c.ended {
+		c.flushLocked()
+		c.t.Errorf("%s", message)
+		return
+	}
+	f := failure{goroutine: id, location: callerLocation(), message: message, attrs: c.attrs[id]}
+	delete(c.attrs, id)
+	if c.ended {
+		fmt.Fprintf(c.late, "expect: late failure in %v, after the test ended\n", f)
+		return
+	}
+	c.pending = append(c.pending, f)
+}
+
+// markFatal marks the last failure buffered from goroutine id as fatal, or
+// buffers one if there is none.
+func (c *ConcurrentT) markFatal(id uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := len(c.pending) - 1; i >= 0; i-- {
+		if c.pending[i].goroutine == id {
+			c.pending[i].message += fatalNote
+			return
+		}
+	}
+	f := failure{goroutine: id, location: callerLocation(), message: "FailNow called" + fatalNote}
+	if c.ended {
+		fmt.Fprintf(c.late, "expect: late failure in %v, after the test ended\n", f)
+		return
+	}
+	c.pending = append(c.pending, f)
+}
+
+func (c *ConcurrentT) flushLocked() {
+	c.t.Helper()
+	at, _ := c.t.(attrT)
+	for _, f := range c.pending {
+		for i := 0; at != nil && i+1 < len(f.attrs); i += 2 {
+			at.Attr(f.attrs[i], f.attrs[i+1])
+		}
+		c.t.Errorf("%v", f)
+	}
+	c.pending = nil
+}
+
+// goroutineID returns the ID of the calling goroutine.
+func goroutineID() uint64 {
+	var buf [64]byte
+	g, _ := parseGoroutine(string(buf[:runtime.Stack(buf[:], false)]))
+	return g.id
+}
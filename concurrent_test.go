@@ -0,0 +1,175 @@
+package expect
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingT records every failure and attribute, and runs cleanups and
+// FailNow like *testing.T.
+type recordingT struct {
+	cleanupMockT
+	messages []string
+	attrs    []string
+	failNow  bool
+}
+
+func (m *recordingT) Errorf(format string, args ...any) {
+	m.mockT.Errorf(format, args...)
+	m.messages = append(m.messages, m.message)
+}
+
+func (m *recordingT) Attr(key, value string) {
+	m.attrs = append(m.attrs, key+"="+value)
+}
+
+func (m *recordingT) FailNow() {
+	m.failNow = true
+}
+
+// inGoroutine calls fn in a new goroutine and waits for it to return or
+// exit.
+func inGoroutine(fn func()) {
+	var wg sync.WaitGroup
+	wg.Go(fn)
+	wg.Wait()
+}
+
+func TestConcurrent(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &recordingT{}
+		ct := Concurrent(m)
+		inGoroutine(func() { Equal(ct, 1, 1) })
+		m.runCleanups()
+		if m.failed {
+			t.Errorf("expected pass, got %q", m.messages)
+		}
+	})
+
+	t.Run("fail buffered until flush", func(t *testing.T) {
+		m := &recordingT{}
+		ct := Concurrent(m)
+		inGoroutine(func() { Equal(ct, 1, 2) })
+		if m.failed {
+			t.Fatal("expected failure to be buffered")
+		}
+		ct.Flush()
+		if len(m.messages) != 1 || !strings.Contains(m.messages[0], "concurrent_test.go:") ||
+			!strings.HasSuffix(m.messages[0], "expected 1, got 2") {
+			t.Errorf("expected failure with its location, got %q", m.messages)
+		}
+	})
+
+	t.Run("fail flushed at cleanup", func(t *testing.T) {
+		m := &recordingT{}
+		ct := Concurrent(m)
+		inGoroutine(func() { True(ct, false) })
+		m.runCleanups()
+		if len(m.messages) != 1 || !strings.HasPrefix(m.messages[0], "goroutine ") {
+			t.Errorf("expected forwarded failure, got %q", m.messages)
+		}
+	})
+
+	t.Run("fail in order", func(t *testing.T) {
+		m := &recordingT{}
+		ct := Concurrent(m)
+		inGoroutine(func() { ct.Errorf("first") })
+		ct.Errorf("second")
+		if len(m.messages) != 2 || !strings.HasSuffix(m.messages[0], ": first") || m.messages[1] != "second" {
+			t.Errorf("expected buffered failure first, got %q", m.messages)
+		}
+	})
+
+	t.Run("fail with attributes", func(t *testing.T) {
+		m := &recordingT{}
+		ct := Concurrent(m)
+		inGoroutine(func() { Equal(ct, 1, 2) })
+		ct.Flush()
+		if !strings.Contains(fmt.Sprint(m.attrs), AttrAssertion+"=Equal") {
+			t.Errorf("expected forwarded attributes, got %q", m.attrs)
+		}
+	})
+
+	t.Run("fatal off test goroutine", func(t *testing.T) {
+		m := &recordingT{}
+		ct := Concurrent(m)
+		reached := false
+		inGoroutine(func() {
+			ct.Fatalf("stop")
+			reached = true
+		})
+		ct.Flush()
+		if reached || m.failNow {
+			t.Error("expected only the goroutine to stop")
+		}
+		if len(m.messages) != 1 || !strings.HasSuffix(m.messages[0], "stop (fatal: goroutine stopped)") {
+			t.Errorf("expected fatal failure, got %q", m.messages)
+		}
+	})
+
+	t.Run("FailNow off test goroutine", func(t *testing.T) {
+		m := &recordingT{}
+		ct := Concurrent(m)
+		inGoroutine(func() {
+			ct.Errorf("first")
+			ct.Errorf("last")
+			ct.FailNow()
+		})
+		inGoroutine(ct.FailNow)
+		ct.Flush()
+		if len(m.messages) != 3 || !strings.HasSuffix(m.messages[1], "last"+fatalNote) ||
+			!strings.HasSuffix(m.messages[2], "FailNow called"+fatalNote) {
+			t.Errorf("expected fatal failures, got %q", m.messages)
+		}
+	})
+
+	t.Run("fatal on test goroutine", func(t *testing.T) {
+		m := &recordingT{}
+		ct := Concurrent(m)
+		inGoroutine(func() { ct.Errorf("background") })
+		ct.FailNow()
+		if !m.failNow || len(m.messages) != 1 {
+			t.Errorf("expected flush and FailNow, got %q", m.messages)
+		}
+	})
+
+	t.Run("late failure", func(t *testing.T) {
+		m := &recordingT{}
+		ct := Concurrent(m)
+		var late strings.Builder
+		ct.late = &late
+		m.runCleanups()
+		inGoroutine(func() { Equal(ct, 1, 2) })
+		ct.Errorf("on test goroutine")
+		if m.failed {
+			t.Errorf("expected no failure after the test ended, got %q", m.messages)
+		}
+		if got := late.String(); strings.Count(got, "expect: late failure in goroutine") != 2 ||
+			!strings.Contains(got, "expected 1, got 2") {
+			t.Errorf("expected late failures, got %q", got)
+		}
+	})
+
+	t.Run("testing.T", func(t *testing.T) {
+		ct := Concurrent(t)
+		var wg sync.WaitGroup
+		for i := range 10 {
+			wg.Go(func() { Equal(ct, i, i) })
+		}
+		wg.Wait()
+	})
+}
+
+func TestGoroutineID(t *testing.T) {
+	id := goroutineID()
+	if id == 0 || goroutineID() != id {
+		t.Fatalf("expected stable goroutine ID, got %d", id)
+	}
+	var other uint64
+	inGoroutine(func() { other = goroutineID() })
+	if other == 0 || other == id {
+		t.Errorf("expected distinct goroutine ID, got %d and %d", id, other)
+	}
+}
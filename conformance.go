@@ -0,0 +1,149 @@
+package expect
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+)
+
+// maxBreakChecks bounds the number of break positions ConformsSeq checks.
+const maxBreakChecks = 64
+
+// ConformsSeq asserts that the sequences returned by newSeq follow the
+// iterator protocol and yield expected. Like testing/iotest.TestReader, it
+// exercises a fresh sequence in several scenarios:
+//
+//   - a full iteration yields exactly expected;
+//   - breaking out of the loop after each element stops the sequence, which
+//     must not call yield again after it returned false;
+//   - iterating the same sequence twice does not panic, and yields expected
+//     again unless the sequence is single-use and yields nothing.
+//
+// Panics are recovered and reported as violations.
+func ConformsSeq[E comparable](t T, expected []E, newSeq func() iter.Seq[E]) {
+	t.Helper()
+	if violations := conformance(expected, newSeq); len(violations) > 0 {
+		errorf(t, "ConformsSeq", "sequence does not conform to the iterator protocol:\n  %s", strings.Join(violations, "\n  "))
+	}
+}
+
+// ConformsSeq2 is like [ConformsSeq] for iter.Seq2, with the pairs of
+// expected as the expected elements. expected must yield at most a million
+// pairs.
+func ConformsSeq2[K, V comparable](t T, expected iter.Seq2[K, V], newSeq func() iter.Seq2[K, V]) {
+	t.Helper()
+	want := consume(pairs(expected), maxSeqLen, nil)
+	if !checkYield(t, "ConformsSeq2", want) || !checkExpectedLen(t, "ConformsSeq2", want) {
+		return
+	}
+	violations := conformance(want.items, func() iter.Seq[pair[K, V]] { return pairs(newSeq()) })
+	if len(violations) > 0 {
+		errorf(t, "ConformsSeq2", "sequence does not conform to the iterator protocol:\n  %s", strings.Join(violations, "\n  "))
+	}
+}
+
+// ReleasesSeq asserts that the sequences returned by newSeq release the
+// resources they hold however iteration ends: after a full iteration,
+// after a break, and when the loop body panics. open reports the number of
+// resources currently held, such as open files or connections, and must
+// return to its previous value after each scenario.
+//
+//	expect.ReleasesSeq(t, func() iter.Seq[Row] { return db.Rows(query) }, db.OpenCursors)
+func ReleasesSeq[E any](t T, newSeq func() iter.Seq[E], open func() int) {
+	t.Helper()
+	scenarios := []struct {
+		name string
+		loop func(E) bool
+	}{
+		{"full iteration", func(E) bool { return true }},
+		{"break after the first element", func(E) bool { return false }},
+		{"panic in the loop body", func(E) bool { panic(loopPanic{}) }},
+	}
+
+	var violations []string
+	for _, s := range scenarios {
+		before, count := open(), 0
+		if r := catch(func() {
+			newSeq()(func(e E) bool {
+				count++
+				return count < maxSeqLen && s.loop(e)
+			})
+		}); r != nil && r != (loopPanic{}) {
+			violations = append(violations, fmt.Sprintf("%s: panicked: %v", s.name, r))
+		}
+		if held := open() - before; held != 0 {
+			violations = append(violations, fmt.Sprintf("%s: %d resources not released", s.name, held))
+		}
+	}
+	if len(violations) > 0 {
+		errorf(t, "ReleasesSeq", "sequence does not release its resources:\n  %s", strings.Join(violations, "\n  "))
+	}
+}
+
+// loopPanic is the value ReleasesSeq panics with in the loop body.
+type loopPanic struct{}
+
+// conformance runs the ConformsSeq scenarios and returns their violations.
+func conformance[E comparable](expected []E, newSeq func() iter.Seq[E]) []string {
+	var violations []string
+	report := func(scenario, format string, args ...any) {
+		violations = append(violations, scenario+": "+fmt.Sprintf(format, args...))
+	}
+	run := func(scenario string, seq iter.Seq[E], stopAfter int) (consumed[E], bool) {
+		var got consumed[E]
+		n := 0
+		if r := catch(func() {
+			got = consume(seq, len(expected), func(E) bool {
+				n++
+				return n == stopAfter
+			})
+		}); r != nil {
+			report(scenario, "panicked: %v", r)
+			return got, false
+		}
+		if got.yieldAfterStop {
+			report(scenario, "yield called after it returned false")
+			return got, false
+		}
+		return got, true
+	}
+
+	if got, ok := run("full iteration", newSeq(), -1); ok && !slices.Equal(expected, got.items) {
+		report("full iteration", "yielded %v, want %s", got, formatItems(expected, false))
+	}
+
+	for _, k := range breakPoints(len(expected)) {
+		scenario := fmt.Sprintf("break after %d elements", k+1)
+		if got, ok := run(scenario, newSeq(), k+1); ok && !slices.Equal(expected[:k+1], got.items) {
+			report(scenario, "yielded %v, want %s", got, formatItems(expected[:k+1], false))
+		}
+	}
+
+	seq := newSeq()
+	if _, ok := run("first of two iterations", seq, -1); ok {
+		got, ok := run("second of two iterations", seq, -1)
+		if ok && len(got.items) > 0 && !slices.Equal(expected, got.items) {
+			report("second of two iterations", "yielded %v, want %s or nothing", got, formatItems(expected, false))
+		}
+	}
+	return violations
+}
+
+func breakPoints(n int) []int {
+	points := make([]int, 0, min(n, maxBreakChecks+1))
+	for k := range min(n, maxBreakChecks) {
+		points = append(points, k)
+	}
+	if n > maxBreakChecks {
+		points = append(points, n-1)
+	}
+	return points
+}
+
+// catch calls fn and returns the value it panicked with, if any.
+func catch(fn func()) (r any) {
+	defer func() { r = recover() }()
+	fn()
+	return nil
+}
@@ -0,0 +1,181 @@
+package expect
+
+import (
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// singleUse returns a sequence that yields values on its first iteration
+// only, like a sequence reading from a stream.
+func singleUse(values []int) iter.Seq[int] {
+	done := false
+	return func(yield func(int) bool) {
+		if done {
+			return
+		}
+		done = true
+		for _, v := range values {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// panicsOnReuse returns a sequence that panics when iterated twice.
+func panicsOnReuse(values []int) iter.Seq[int] {
+	var ch chan int
+	return func(yield func(int) bool) {
+		if ch != nil {
+			close(ch)
+		}
+		ch = make(chan int)
+		close(ch)
+		for _, v := range values {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func TestConformsSeq(t *testing.T) {
+	values := []int{1, 2, 3}
+
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		ConformsSeq(m, values, func() iter.Seq[int] { return slices.Values(values) })
+		ConformsSeq(m, nil, func() iter.Seq[int] { return slices.Values([]int{}) })
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("pass single use", func(t *testing.T) {
+		m := &mockT{}
+		ConformsSeq(m, values, func() iter.Seq[int] { return singleUse(values) })
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail ignores stop", func(t *testing.T) {
+		m := &mockT{}
+		ConformsSeq(m, []int{0, 1, 2}, func() iter.Seq[int] { return ignoresStop })
+		if !m.failed {
+			t.Error("expected fail")
+		}
+		want := "sequence does not conform to the iterator protocol:\n" +
+			"  break after 1 elements: yield called after it returned false\n" +
+			"  break after 2 elements: yield called after it returned false"
+		if m.message != want {
+			t.Errorf("got:\n%s\nwant:\n%s", m.message, want)
+		}
+	})
+
+	t.Run("fail values", func(t *testing.T) {
+		m := &mockT{}
+		ConformsSeq(m, []int{1, 2}, func() iter.Seq[int] { return slices.Values(values) })
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail panics on reuse", func(t *testing.T) {
+		m := &mockT{}
+		ConformsSeq(m, values, func() iter.Seq[int] { return panicsOnReuse(values) })
+		if !m.failed {
+			t.Error("expected fail")
+		}
+		if !strings.Contains(m.message, "second of two iterations: panicked: close of closed channel") {
+			t.Errorf("expected panic violation, got:\n%s", m.message)
+		}
+	})
+}
+
+func TestConformsSeq2(t *testing.T) {
+	values := map[string]int{"a": 1}
+
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		ConformsSeq2(m, maps.All(values), func() iter.Seq2[string, int] { return maps.All(values) })
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		ConformsSeq2(m, maps.All(values), func() iter.Seq2[string, int] { return maps.All(map[string]int{"a": 2}) })
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail infinite expected", func(t *testing.T) {
+		m := &mockT{}
+		ConformsSeq2(m, naturals2, func() iter.Seq2[int, int] { return naturals2 })
+		if want := "expected sequence exceeds 1000000 elements"; m.message != want {
+			t.Errorf("got %q, want %q", m.message, want)
+		}
+	})
+}
+
+// resource counts open handles for ReleasesSeq tests.
+type resource struct{ open int }
+
+func (r *resource) count() int { return r.open }
+
+func (r *resource) rows(n int, leakOnBreak bool) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		r.open++
+		if !leakOnBreak {
+			defer func() { r.open-- }()
+		}
+		for i := range n {
+			if !yield(i) {
+				return
+			}
+		}
+		if leakOnBreak {
+			r.open--
+		}
+	}
+}
+
+func TestReleasesSeq(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		r := &resource{}
+		ReleasesSeq(m, func() iter.Seq[int] { return r.rows(3, false) }, r.count)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("pass infinite", func(t *testing.T) {
+		m := &mockT{}
+		ReleasesSeq(m, func() iter.Seq[int] { return naturals }, func() int { return 0 })
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		r := &resource{}
+		ReleasesSeq(m, func() iter.Seq[int] { return r.rows(3, true) }, r.count)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+		want := "sequence does not release its resources:\n" +
+			"  break after the first element: 1 resources not released\n" +
+			"  panic in the loop body: 1 resources not released"
+		if m.message != want {
+			t.Errorf("got:\n%s\nwant:\n%s", m.message, want)
+		}
+	})
+}
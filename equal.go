@@ -0,0 +1,375 @@
+package expect
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxDifferences bounds the number of differences listed in a failure.
+const maxDifferences = 10
+
+// DeepEqual asserts that expected and actual are deeply equal. Values are
+// compared like [reflect.DeepEqual], except that a type with an
+// Equal(T) bool method, such as time.Time, is compared with that method.
+// reflect does not allow calling the method on values held in unexported
+// fields, so those are compared field by field, and a time.Time in an
+// unexported field may need [IgnoreFields]. Options adjust the comparison.
+// On failure, the message lists the paths of the differing values, as
+// returned by [Diff].
+func DeepEqual[V any](t T, expected, actual V, opts ...Option) {
+	t.Helper()
+	if diffs := differences(expected, actual, opts); len(diffs) > 0 {
+		errorfValues(t, "DeepEqual", expected, actual, "expected %v, got %v\n%s", expected, actual, formatDifferences(diffs))
+	}
+}
+
+// NotDeepEqual asserts that unexpected and actual are not deeply equal, as
+// defined by [DeepEqual].
+func NotDeepEqual[V any](t T, unexpected, actual V, opts ...Option) {
+	t.Helper()
+	if len(differences(unexpected, actual, opts)) == 0 {
+		failMatch(t, "NotDeepEqual", actual)
+	}
+}
+
+// Diff returns a description of the differences between expected and actual
+// as compared by [DeepEqual], or an empty string if they are deeply equal.
+func Diff[V any](expected, actual V, opts ...Option) string {
+	diffs := differences(expected, actual, opts)
+	if len(diffs) == 0 {
+		return ""
+	}
+	return formatDifferences(diffs)
+}
+
+// difference is a value that differs between the expected and actual side
+// of a deep comparison. A side is empty when the value is missing from it.
+type difference struct {
+	path     string
+	expected string
+	actual   string
+}
+
+func differences(expected, actual any, opts []Option) []difference {
+	return newComparison(opts).run(expected, actual)
+}
+
+func formatDifferences(diffs []difference) string {
+	var b strings.Builder
+	b.WriteString("differences:")
+	for i, d := range diffs {
+		if i == maxDifferences {
+			fmt.Fprintf(&b, "\n  ... and %d more", len(diffs)-i)
+			break
+		}
+		b.WriteString("\n  ")
+		if d.path != "" {
+			b.WriteString(d.path + ": ")
+		}
+		switch {
+		case d.expected == "":
+			fmt.Fprintf(&b, "unexpected %s", d.actual)
+		case d.actual == "":
+			fmt.Fprintf(&b, "missing %s", d.expected)
+		default:
+			fmt.Fprintf(&b, "expected %s, got %s", d.expected, d.actual)
+		}
+	}
+	return b.String()
+}
+
+// visit identifies a pair of references already being compared, so that
+// cyclic values terminate. Slices sharing a backing array are only the same
+// reference if they also have the same lengths.
+type visit struct {
+	x, y       uintptr
+	xLen, yLen int
+	typ        reflect.Type
+}
+
+// path locates a value within a compared value. shown is the path used in
+// failure messages, and fields is the path options are matched against.
+type path struct {
+	shown  string
+	fields string
+}
+
+func (p path) field(name string) path {
+	return path{p.shown + "." + name, joinFields(p.fields, name)}
+}
+
+func (p path) index(i int) path {
+	return path{p.shown + "[" + strconv.Itoa(i) + "]", p.fields}
+}
+
+func (p path) key(key reflect.Value) path {
+	return path{p.shown + "[" + formatValue(key) + "]", p.fields}
+}
+
+type comparison struct {
+	opts    *options
+	visited map[visit]bool
+	diffs   []difference
+
+	// partial restricts the comparison to the non-zero fields and the map
+	// keys of the expected value.
+	partial bool
+	// fields, if set, are the only field paths compared. The values
+	// enclosing them are compared field by field.
+	fields []string
+}
+
+func newComparison(opts []Option) *comparison {
+	return &comparison{opts: newOptions(opts), visited: map[visit]bool{}}
+}
+
+func (c *comparison) run(expected, actual any) []difference {
+	c.compare(path{}, reflect.ValueOf(expected), reflect.ValueOf(actual))
+	return c.diffs
+}
+
+func (c *comparison) report(p path, x, y reflect.Value) {
+	d := difference{path: p.shown}
+	if x.IsValid() {
+		d.expected = formatValue(x)
+	}
+	if y.IsValid() {
+		d.actual = formatValue(y)
+	}
+	c.diffs = append(c.diffs, d)
+}
+
+func (c *comparison) compare(p path, x, y reflect.Value) {
+	rules := c.opts.active(p.fields)
+	if !x.IsValid() || !y.IsValid() {
+		if x.IsValid() != y.IsValid() {
+			c.report(p, x, y)
+		}
+		return
+	}
+	for _, r := range rules {
+		if r.transform != nil {
+			x, _ = r.transform(x)
+			y, _ = r.transform(y)
+		}
+	}
+	if x.Type() != y.Type() {
+		c.diffs = append(c.diffs, difference{
+			path:     p.shown,
+			expected: fmt.Sprintf("%s (%v)", formatValue(x), x.Type()),
+			actual:   fmt.Sprintf("%s (%v)", formatValue(y), y.Type()),
+		})
+		return
+	}
+
+	for _, r := range rules {
+		if r.equal == nil {
+			continue
+		}
+		if eq, ok := r.equal(x, y); ok {
+			if !eq {
+				c.report(p, x, y)
+			}
+			return
+		}
+	}
+	if eq, ok := c.equalMethod(p, x, y); ok {
+		if !eq {
+			c.report(p, x, y)
+		}
+		return
+	}
+
+	switch x.Kind() {
+	case reflect.Pointer:
+		if x.IsNil() || y.IsNil() || x.Pointer() == y.Pointer() {
+			if x.IsNil() != y.IsNil() {
+				c.report(p, x, y)
+			}
+			return
+		}
+		if c.seen(x, y) {
+			return
+		}
+		c.compare(p, x.Elem(), y.Elem())
+	case reflect.Interface:
+		if x.IsNil() || y.IsNil() {
+			if x.IsNil() != y.IsNil() {
+				c.report(p, x, y)
+			}
+			return
+		}
+		c.compare(p, x.Elem(), y.Elem())
+	case reflect.Struct:
+		for i := range x.NumField() {
+			field := x.Type().Field(i)
+			fp := p.field(field.Name)
+			if c.partial && x.Field(i).IsZero() {
+				continue
+			}
+			if !c.ignored(field, fp) {
+				c.compare(fp, x.Field(i), y.Field(i))
+			}
+		}
+	case reflect.Slice:
+		if x.IsNil() != y.IsNil() {
+			c.report(p, x, y)
+			return
+		}
+		if x.Len() > 0 && y.Len() > 0 && c.seen(x, y) {
+			return
+		}
+		for _, r := range rules {
+			if r.sort == nil {
+				continue
+			}
+			if sx, ok := r.sort(x); ok {
+				sy, _ := r.sort(y)
+				x, y = sx, sy
+				break
+			}
+		}
+		c.compareElements(p, x, y)
+	case reflect.Array:
+		c.compareElements(p, x, y)
+	case reflect.Map:
+		if x.IsNil() != y.IsNil() {
+			c.report(p, x, y)
+			return
+		}
+		if c.seen(x, y) {
+			return
+		}
+		keys := mapKeys(x, y)
+		if c.partial {
+			keys = mapKeys(x, x)
+		}
+		for _, key := range keys {
+			c.compare(p.key(key), x.MapIndex(key), y.MapIndex(key))
+		}
+	case reflect.Func:
+		if !x.IsNil() || !y.IsNil() {
+			c.report(p, x, y)
+		}
+	default:
+		if !x.Equal(y) {
+			c.report(p, x, y)
+		}
+	}
+}
+
+func (c *comparison) ignored(field reflect.StructField, p path) bool {
+	for _, r := range c.opts.active(p.fields) {
+		if r.ignore != nil && r.ignore(field, p.fields) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *comparison) compareElements(p path, x, y reflect.Value) {
+	for i := range max(x.Len(), y.Len()) {
+		var xi, yi reflect.Value
+		if i < x.Len() {
+			xi = x.Index(i)
+		}
+		if i < y.Len() {
+			yi = y.Index(i)
+		}
+		c.compare(p.index(i), xi, yi)
+	}
+}
+
+// seen records that x and y are being compared and reports whether they
+// already were.
+func (c *comparison) seen(x, y reflect.Value) bool {
+	v := visit{x: x.Pointer(), y: y.Pointer(), typ: x.Type()}
+	if x.Kind() == reflect.Slice {
+		v.xLen, v.yLen = x.Len(), y.Len()
+	}
+	if c.visited[v] {
+		return true
+	}
+	c.visited[v] = true
+	return false
+}
+
+// equalMethod compares x and y with an Equal(T) bool method of their type T,
+// and reports whether such a method was found. Partial comparisons match
+// structs with exported fields field by field instead, as do comparisons
+// of some fields of a value.
+func (c *comparison) equalMethod(p path, x, y reflect.Value) (bool, bool) {
+	if c.partial && x.Kind() == reflect.Struct && hasExportedFields(x.Type()) {
+		return false, false
+	}
+	if c.fields != nil && slices.ContainsFunc(c.fields, func(field string) bool {
+		return p.fields == "" || strings.HasPrefix(field, p.fields+".")
+	}) {
+		return false, false
+	}
+	if !x.CanInterface() || !y.CanInterface() {
+		return false, false
+	}
+	typ := x.Type()
+	m, ok := typ.MethodByName("Equal")
+	if !ok {
+		return false, false
+	}
+	ft := m.Type
+	if ft.NumIn() != 2 || ft.In(1) != typ || ft.NumOut() != 1 || ft.Out(0).Kind() != reflect.Bool {
+		return false, false
+	}
+	if typ.Kind() == reflect.Pointer && (x.IsNil() || y.IsNil()) {
+		return x.IsNil() && y.IsNil(), true
+	}
+	return m.Func.Call([]reflect.Value{x, y})[0].Bool(), true
+}
+
+// mapKeys returns the union of the keys of x and y in a deterministic order.
+func mapKeys(x, y reflect.Value) []reflect.Value {
+	keys := x.MapKeys()
+	for _, key := range y.MapKeys() {
+		if !x.MapIndex(key).IsValid() {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, compareKeys)
+	return keys
+}
+
+// compareKeys orders map keys naturally when they are numbers or strings,
+// and by their formatted value otherwise.
+func compareKeys(a, b reflect.Value) int {
+	if a.Type() != b.Type() {
+		return strings.Compare(a.Type().String(), b.Type().String())
+	}
+	switch {
+	case a.CanInt():
+		return cmp.Compare(a.Int(), b.Int())
+	case a.CanUint():
+		return cmp.Compare(a.Uint(), b.Uint())
+	case a.CanFloat():
+		return cmp.Compare(a.Float(), b.Float())
+	case a.Kind() == reflect.String:
+		return strings.Compare(a.String(), b.String())
+	}
+	return strings.Compare(formatValue(a), formatValue(b))
+}
+
+// formatValue formats v for a difference. Nil slices and maps are shown as
+// <nil>, to tell them apart from empty ones.
+func formatValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return "<nil>"
+		}
+	}
+	return fmt.Sprintf("%v", v)
+}
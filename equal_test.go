@@ -0,0 +1,163 @@
+package expect
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type money struct {
+	cents    int
+	currency string
+}
+
+func (m money) Equal(other money) bool {
+	return m.cents == other.cents && (m.currency == other.currency || m.cents == 0)
+}
+
+type order struct {
+	ID      int
+	Total   money
+	Created time.Time
+	Items   []string
+	Meta    map[string]any
+	Next    *order
+}
+
+func TestDeepEqual(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		expected := order{ID: 1, Items: []string{"a"}, Meta: map[string]any{"k": 1}}
+		actual := order{ID: 1, Items: []string{"a"}, Meta: map[string]any{"k": 1}}
+		DeepEqual(m, expected, actual)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("pass equal methods", func(t *testing.T) {
+		m := &mockT{}
+		expected := order{Total: money{0, "EUR"}, Created: created}
+		actual := order{Total: money{0, "USD"}, Created: created.In(time.FixedZone("X", 3600))}
+		DeepEqual(m, expected, actual)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("pass cycle", func(t *testing.T) {
+		m := &mockT{}
+		a, b := &order{ID: 1}, &order{ID: 1}
+		a.Next, b.Next = a, b
+		DeepEqual(m, a, b)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		DeepEqual(m, order{ID: 1, Items: []string{"a"}}, order{ID: 1, Items: []string{"b"}})
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail equal method", func(t *testing.T) {
+		m := &mockT{}
+		DeepEqual(m, money{1, "EUR"}, money{1, "USD"})
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail nil and empty", func(t *testing.T) {
+		m := &mockT{}
+		DeepEqual(m, []int(nil), []int{})
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail shared backing array", func(t *testing.T) {
+		type S struct{ A, B []int }
+		m := &mockT{}
+		x, y := []int{1, 2, 3}, []int{1, 9, 9}
+		DeepEqual(m, S{x[:1], x[:3]}, S{y[:1], y[:3]})
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestNotDeepEqual(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		NotDeepEqual(m, []int{1}, []int{2})
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		NotDeepEqual(m, money{0, "EUR"}, money{0, "USD"})
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestDifferences(t *testing.T) {
+	expected := order{
+		ID:    1,
+		Total: money{100, "EUR"},
+		Items: []string{"a", "b"},
+		Meta:  map[string]any{"a": 1, "b": "x", "c": true},
+	}
+	actual := order{
+		ID:    2,
+		Total: money{100, "EUR"},
+		Items: []string{"a", "c", "d"},
+		Meta:  map[string]any{"a": 1, "b": 2, "d": nil},
+		Next:  &order{},
+	}
+	want := `differences:
+  .ID: expected 1, got 2
+  .Items[1]: expected "b", got "c"
+  .Items[2]: unexpected "d"
+  .Meta["b"]: expected "x" (string), got 2 (int)
+  .Meta["c"]: missing true
+  .Meta["d"]: unexpected <nil>
+  .Next: expected <nil>, got &{0 {0 } 0001-01-01 00:00:00 +0000 UTC [] map[] <nil>}`
+	if got := formatDifferences(differences(expected, actual, nil)); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDifferencesNil(t *testing.T) {
+	type sets struct {
+		M map[string]int
+		S []int
+	}
+	want := `differences:
+  .M: expected <nil>, got map[]
+  .S: expected [], got <nil>`
+	if got := Diff(sets{S: []int{}}, sets{M: map[string]int{}}); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFormatDifferencesLimit(t *testing.T) {
+	expected := make([]int, maxDifferences+3)
+	actual := make([]int, maxDifferences+3)
+	for i := range actual {
+		actual[i] = i + 1
+	}
+	got := formatDifferences(differences(expected, actual, nil))
+	if !strings.HasSuffix(got, "\n  ... and 3 more") {
+		t.Errorf("expected truncated differences, got:\n%s", got)
+	}
+}
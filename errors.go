@@ -0,0 +1,194 @@
+package expect
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// EqualError asserts that err is not nil and its message equals message.
+func EqualError(t T, err error, message string) {
+	t.Helper()
+	if err == nil {
+		errorf(t, "EqualError", "expected error %q, got nil", message)
+		return
+	}
+	if err.Error() != message {
+		errorfValues(t, "EqualError", message, err.Error(), "expected error message %q, got %q\n%s", message, err.Error(), errorTree(err))
+	}
+}
+
+// ErrorContains asserts that err is not nil and its message contains substr.
+func ErrorContains(t T, err error, substr string) {
+	t.Helper()
+	if err == nil {
+		errorf(t, "ErrorContains", "expected error containing %q, got nil", substr)
+		return
+	}
+	if !strings.Contains(err.Error(), substr) {
+		errorf(t, "ErrorContains", "expected error message %q to contain %q\n%s", err.Error(), substr, errorTree(err))
+	}
+}
+
+// ErrorMatches asserts that err is not nil and its message matches the
+// regular expression pattern.
+func ErrorMatches(t T, err error, pattern string) {
+	t.Helper()
+	re, compileErr := regexp.Compile(pattern)
+	if compileErr != nil {
+		errorf(t, "ErrorMatches", "invalid pattern %q: %v", pattern, compileErr)
+		return
+	}
+	if err == nil {
+		errorf(t, "ErrorMatches", "expected error matching %q, got nil", pattern)
+		return
+	}
+	if !re.MatchString(err.Error()) {
+		errorf(t, "ErrorMatches", "expected error message %q to match %q\n%s", err.Error(), pattern, errorTree(err))
+	}
+}
+
+// ErrorIsAll asserts that err matches every target using errors.Is, as for
+// an error built with errors.Join.
+func ErrorIsAll(t T, err error, targets ...error) {
+	t.Helper()
+	if missing := missingTargets(err, targets); len(missing) > 0 {
+		errorf(t, "ErrorIsAll", "expected error to match %v\n%s", formatErrors(missing), errorTree(err))
+	}
+}
+
+// ErrorIsExactly asserts that err matches every target using errors.Is, and
+// that every leaf of its tree of wrapped and joined errors matches one of
+// the targets. A target that wraps other errors matches its whole subtree.
+func ErrorIsExactly(t T, err error, targets ...error) {
+	t.Helper()
+	missing := missingTargets(err, targets)
+	extra := unmatchedLeaves(err, targets)
+	if len(missing) == 0 && len(extra) == 0 {
+		return
+	}
+
+	var b strings.Builder
+	if len(missing) > 0 {
+		fmt.Fprintf(&b, "expected error to match %v", formatErrors(missing))
+	}
+	if len(extra) > 0 {
+		if b.Len() > 0 {
+			b.WriteString("; ")
+		}
+		fmt.Fprintf(&b, "unexpected errors %v", formatErrors(extra))
+	}
+	errorf(t, "ErrorIsExactly", "%s\n%s", b.String(), errorTree(err))
+}
+
+func missingTargets(err error, targets []error) []error {
+	var missing []error
+	for _, target := range targets {
+		if !errors.Is(err, target) {
+			missing = append(missing, target)
+		}
+	}
+	return missing
+}
+
+// isTarget reports whether err itself matches one of targets, as errors.Is
+// checks each error of a tree: by equality, or with an Is method. Like
+// errors.Is, it matches a nil target only to a nil err.
+func isTarget(err error, targets []error) bool {
+	for _, target := range targets {
+		if target == nil {
+			if err == nil {
+				return true
+			}
+			continue
+		}
+		if reflect.TypeOf(target).Comparable() && err == target {
+			return true
+		}
+		if x, ok := err.(interface{ Is(error) bool }); ok && x.Is(target) {
+			return true
+		}
+	}
+	return false
+}
+
+// unmatchedLeaves returns the errors in err's tree that wrap no other error
+// and match none of targets. Errors that match a target are not unwrapped.
+func unmatchedLeaves(err error, targets []error) []error {
+	if err == nil || isTarget(err, targets) {
+		return nil
+	}
+	children := unwrapAll(err)
+	if len(children) == 0 {
+		return []error{err}
+	}
+	var leaves []error
+	for _, child := range children {
+		leaves = append(leaves, unmatchedLeaves(child, targets)...)
+	}
+	return leaves
+}
+
+// unwrapAll returns the errors directly wrapped by err.
+func unwrapAll(err error) []error {
+	switch u := err.(type) {
+	case interface{ Unwrap() []error }:
+		return u.Unwrap()
+	case interface{ Unwrap() error }:
+		if inner := u.Unwrap(); inner != nil {
+			return []error{inner}
+		}
+	}
+	return nil
+}
+
+func formatErrors(errs []error) string {
+	parts := make([]string, len(errs))
+	for i, err := range errs {
+		if err == nil {
+			parts[i] = "<nil>"
+			continue
+		}
+		parts[i] = fmt.Sprintf("%q (%T)", err.Error(), err)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// errorTree renders err and the errors it wraps, one per line, with the
+// dynamic type of each:
+//
+//	error tree:
+//	  *fmt.wrapError "load: a\nb"
+//	  └─ *errors.joinError "a\nb"
+//	     ├─ *errors.errorString "a"
+//	     └─ *errors.errorString "b"
+func errorTree(err error) string {
+	var b strings.Builder
+	b.WriteString("error tree:\n")
+	if err == nil {
+		b.WriteString("  <nil>")
+		return b.String()
+	}
+	writeErrorNode(&b, err, "  ", "")
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func writeErrorNode(b *strings.Builder, err error, prefix, branch string) {
+	fmt.Fprintf(b, "%s%s%T %q\n", prefix, branch, err, err.Error())
+	children := unwrapAll(err)
+	switch branch {
+	case "├─ ":
+		prefix += "│  "
+	case "└─ ":
+		prefix += "   "
+	}
+	for i, child := range children {
+		if i == len(children)-1 {
+			writeErrorNode(b, child, prefix, "└─ ")
+		} else {
+			writeErrorNode(b, child, prefix, "├─ ")
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package expect
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+var (
+	errA = errors.New("a")
+	errB = errors.New("b")
+	errC = errors.New("c")
+)
+
+func TestEqualError(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		EqualError(m, errors.New("boom"), "boom")
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail mismatch", func(t *testing.T) {
+		m := &mockT{}
+		EqualError(m, errors.New("boom"), "bang")
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail nil", func(t *testing.T) {
+		m := &mockT{}
+		EqualError(m, nil, "boom")
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestErrorContains(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		ErrorContains(m, fmt.Errorf("open: %w", errA), "open")
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		ErrorContains(m, errA, "open")
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail nil", func(t *testing.T) {
+		m := &mockT{}
+		ErrorContains(m, nil, "")
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestErrorMatches(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		ErrorMatches(m, errors.New("code 404"), `^code \d+$`)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		ErrorMatches(m, errors.New("code x"), `^code \d+$`)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail invalid pattern", func(t *testing.T) {
+		m := &mockT{}
+		ErrorMatches(m, errors.New("code"), `(`)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail nil", func(t *testing.T) {
+		m := &mockT{}
+		ErrorMatches(m, nil, `.*`)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestErrorIsAll(t *testing.T) {
+	joined := fmt.Errorf("load: %w", errors.Join(errA, fmt.Errorf("wrapped: %w", errB)))
+
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		ErrorIsAll(m, joined, errA, errB)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail missing", func(t *testing.T) {
+		m := &mockT{}
+		ErrorIsAll(m, joined, errA, errC)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail nil target", func(t *testing.T) {
+		m := &mockT{}
+		ErrorIsAll(m, joined, errA, nil)
+		if !strings.HasPrefix(m.message, "expected error to match [<nil>]\n") {
+			t.Errorf("unexpected message: %q", m.message)
+		}
+	})
+}
+
+// isError matches errB with its Is method, and wraps err.
+type isError struct{ err error }
+
+func (e isError) Error() string        { return "is b: " + e.err.Error() }
+func (e isError) Unwrap() error        { return e.err }
+func (e isError) Is(target error) bool { return target == errB }
+
+func TestErrorIsExactly(t *testing.T) {
+	joined := fmt.Errorf("load: %w", errors.Join(errA, fmt.Errorf("wrapped: %w", errB)))
+
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		ErrorIsExactly(m, joined, errB, errA)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("pass wrapping target", func(t *testing.T) {
+		m := &mockT{}
+		errBase := errors.New("base")
+		errNotFound := fmt.Errorf("%w: not found", errBase)
+		ErrorIsExactly(m, errors.Join(errNotFound, io.EOF), errNotFound, io.EOF)
+		if m.failed {
+			t.Errorf("expected pass, got %q", m.message)
+		}
+	})
+
+	t.Run("pass Is method", func(t *testing.T) {
+		m := &mockT{}
+		err := errors.Join(isError{fmt.Errorf("detail: %w", errC)}, errA)
+		ErrorIsExactly(m, err, errB, errA)
+		if m.failed {
+			t.Errorf("expected pass, got %q", m.message)
+		}
+	})
+
+	t.Run("fail missing", func(t *testing.T) {
+		m := &mockT{}
+		ErrorIsExactly(m, joined, errA, errB, errC)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail extra", func(t *testing.T) {
+		m := &mockT{}
+		ErrorIsExactly(m, joined, errA)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("nil target", func(t *testing.T) {
+		m := &mockT{}
+		ErrorIsExactly(m, nil, nil)
+		if m.failed {
+			t.Errorf("expected pass, got %q", m.message)
+		}
+		ErrorIsExactly(m, joined, errA, errB, nil)
+		if !strings.HasPrefix(m.message, "expected error to match [<nil>]\n") {
+			t.Errorf("unexpected message: %q", m.message)
+		}
+	})
+
+	t.Run("fail nil", func(t *testing.T) {
+		m := &mockT{}
+		ErrorIsExactly(m, nil, errA)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestErrorTree(t *testing.T) {
+	err := fmt.Errorf("load: %w", errors.Join(errA, fmt.Errorf("wrapped: %w", errB)))
+	want := `error tree:
+  *fmt.wrapError "load: a\nwrapped: b"
+  └─ *errors.joinError "a\nwrapped: b"
+     ├─ *errors.errorString "a"
+     └─ *fmt.wrapError "wrapped: b"
+        └─ *errors.errorString "b"`
+	if got := errorTree(err); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := errorTree(nil); got != "error tree:\n  <nil>" {
+		t.Errorf("unexpected tree for nil: %q", got)
+	}
+}
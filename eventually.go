@@ -0,0 +1,66 @@
+package expect
+
+import (
+	"testing/synctest"
+	"time"
+)
+
+// Eventually asserts that condition returns true within timeout, checking
+// it every interval, and reports whether it did.
+//
+// Inside a testing/synctest bubble, each check first waits with
+// synctest.Wait for the other goroutines in the bubble to block, and time
+// advances without sleeping, so the assertion is deterministic and returns
+// as soon as the bubble's goroutines have done their work. The channel
+// assertions such as [Receives] and [NotReceives] likewise use the fake
+// clock of the bubble for their timeouts.
+//
+//	expect.Eventually(t, func() bool { return cache.Len() == 0 }, time.Second, 10*time.Millisecond)
+func Eventually(t T, condition func() bool, timeout, interval time.Duration) bool {
+	t.Helper()
+	start := time.Now()
+	for checks := 1; ; checks++ {
+		settle()
+		if condition() {
+			return true
+		}
+		if elapsed := time.Since(start); elapsed >= timeout {
+			errorf(t, "Eventually", "expected condition to be met within %v, still unmet after %d checks", timeout, checks)
+			return false
+		}
+		time.Sleep(interval)
+	}
+}
+
+// Consistently asserts that condition returns true every interval for the
+// whole of duration, and reports whether it did. Like [Eventually], it
+// uses the fake clock inside a testing/synctest bubble.
+func Consistently(t T, condition func() bool, duration, interval time.Duration) bool {
+	t.Helper()
+	start := time.Now()
+	for checks := 1; ; checks++ {
+		settle()
+		if !condition() {
+			errorf(t, "Consistently", "expected condition to hold for %v, but it failed after %v on check %d",
+				duration, time.Since(start), checks)
+			return false
+		}
+		if time.Since(start) >= duration {
+			return true
+		}
+		time.Sleep(interval)
+	}
+}
+
+// settle waits for the other goroutines of the calling goroutine's
+// synctest bubble to block, and reports whether the caller is in a bubble.
+// Outside a bubble, synctest.Wait panics and settle returns immediately.
+func settle() (inBubble bool) {
+	defer func() {
+		if recover() != nil {
+			inBubble = false
+		}
+	}()
+	synctest.Wait()
+	return true
+}
@@ -0,0 +1,119 @@
+package expect
+
+import (
+	"sync/atomic"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestEventually(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		var ready atomic.Bool
+		time.AfterFunc(shortWait, func() { ready.Store(true) })
+		if !Eventually(m, ready.Load, longWait, time.Millisecond) || m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		if Eventually(m, func() bool { return false }, shortWait, time.Millisecond) || !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("bubble", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			m := &mockT{}
+			start := time.Now()
+			var ready atomic.Bool
+			go func() {
+				time.Sleep(time.Hour)
+				ready.Store(true)
+			}()
+			if !Eventually(m, ready.Load, 2*time.Hour, time.Minute) || m.failed {
+				t.Error("expected pass")
+			}
+			if elapsed := time.Since(start); elapsed != time.Hour {
+				t.Errorf("expected condition met after exactly 1h of fake time, got %v", elapsed)
+			}
+		})
+	})
+
+	t.Run("bubble fail", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			m := &mockT{}
+			if Eventually(m, func() bool { return false }, time.Hour, time.Minute) || !m.failed {
+				t.Error("expected fail")
+			}
+			if want := "expected condition to be met within 1h0m0s, still unmet after 61 checks"; m.message != want {
+				t.Errorf("got %q, want %q", m.message, want)
+			}
+		})
+	})
+}
+
+func TestConsistently(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		if !Consistently(m, func() bool { return true }, shortWait, time.Millisecond) || m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		var broken atomic.Bool
+		time.AfterFunc(shortWait, func() { broken.Store(true) })
+		if Consistently(m, func() bool { return !broken.Load() }, longWait, time.Millisecond) || !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("bubble", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			m := &mockT{}
+			var broken atomic.Bool
+			time.AfterFunc(30*time.Minute, func() { broken.Store(true) })
+			if Consistently(m, func() bool { return !broken.Load() }, time.Hour, time.Minute) || !m.failed {
+				t.Error("expected fail")
+			}
+			if want := "expected condition to hold for 1h0m0s, but it failed after 30m0s on check 31"; m.message != want {
+				t.Errorf("got %q, want %q", m.message, want)
+			}
+		})
+	})
+}
+
+func TestChannelsInBubble(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		m := &mockT{}
+		ch := make(chan int)
+		go func() {
+			time.Sleep(time.Hour)
+			ch <- 1
+		}()
+		NotReceives(m, ch, 59*time.Minute)
+		ReceivesValue(m, ch, 1, 2*time.Minute)
+
+		done := make(chan struct{})
+		go close(done)
+		Closed(m, done, 0)
+		if m.failed {
+			t.Errorf("expected pass, got %s", m.message)
+		}
+	})
+}
+
+func TestSettle(t *testing.T) {
+	if settle() {
+		t.Error("expected settle outside a bubble to report false")
+	}
+	synctest.Test(t, func(t *testing.T) {
+		if !settle() {
+			t.Error("expected settle inside a bubble to report true")
+		}
+	})
+}
@@ -1,9 +1,20 @@
 package examples
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
+	"iter"
+	"maps"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"testing/synctest"
+	"time"
 
 	"github.com/lumertzg/expect"
 )
@@ -28,6 +39,72 @@ func TestComparisons(t *testing.T) {
 	expect.GreaterOrEqual(t, 2, 2)
 }
 
+func TestCustomEquality(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	local := created.In(time.FixedZone("CET", 3600))
+
+	expect.EqualFunc(t, created, local, time.Time.Equal)
+	expect.EqualSliceFunc(t, []string{"Go"}, []string{"go"}, strings.EqualFold)
+	expect.EqualMapFunc(t, map[string][]int{"a": {1}}, map[string][]int{"a": {1}}, slices.Equal)
+	expect.LessFunc(t, created, created.Add(time.Hour), time.Time.Compare)
+
+	type event struct {
+		Name string
+		At   time.Time
+	}
+	expect.DeepEqual(t, event{"deploy", created}, event{"deploy", local})
+}
+
+func TestComparisonOptions(t *testing.T) {
+	type user struct {
+		Name      string
+		Roles     []string
+		Score     float64
+		UpdatedAt time.Time
+	}
+	want := user{Name: "gopher", Roles: []string{"admin", "dev"}, Score: 0.3}
+	got := user{Name: "Gopher", Roles: []string{"dev", "admin"}, Score: 0.1 + 0.2, UpdatedAt: time.Now()}
+
+	expect.DeepEqual(t, want, got,
+		expect.IgnoreFields("UpdatedAt"),
+		expect.SortSlices(strings.Compare),
+		expect.EquateApprox(0, 1e-9),
+		expect.AtPath("Name", expect.Transform(strings.ToLower)),
+	)
+	expect.Equal(t, "", expect.Diff([]string{}, nil, expect.EquateEmpty()))
+}
+
+func TestPartialMatching(t *testing.T) {
+	type address struct{ City, Country string }
+	type customer struct {
+		ID      int
+		Name    string
+		Address address
+		Tags    map[string]string
+	}
+	got := customer{
+		ID:      7,
+		Name:    "gopher",
+		Address: address{City: "Porto", Country: "PT"},
+		Tags:    map[string]string{"tier": "gold", "source": "web"},
+	}
+
+	expect.PartialEqual(t, customer{Name: "gopher", Address: address{Country: "PT"}}, got)
+	expect.PartialEqual(t, customer{Tags: map[string]string{"tier": "gold"}}, got)
+	expect.MatchFields(t, customer{ID: 7, Address: address{City: "Porto"}}, got, "ID", "Address.City")
+}
+
+func TestOrdering(t *testing.T) {
+	expect.IsSorted(t, []int{1, 2, 2, 3})
+	expect.IsStrictlyIncreasing(t, []int{1, 2, 3})
+	expect.IsStrictlyDecreasing(t, []string{"c", "b", "a"})
+	expect.IsSortedFunc(t, []time.Duration{time.Second, time.Minute}, cmp.Compare)
+	expect.IsSortedBy(t, []string{"go", "rust", "python"}, func(s string) int { return len(s) })
+
+	expect.Between(t, 5, 1, 10)
+	expect.InRange(t, 0, 0, 3)
+}
+
 func TestBooleans(t *testing.T) {
 	expect.True(t, 10 > 5)
 	expect.False(t, 10 < 5)
@@ -43,15 +120,50 @@ func TestNil(t *testing.T) {
 func TestErrors(t *testing.T) {
 	baseErr := errors.New("something went wrong")
 	wrappedErr := fmt.Errorf("request failed: %w", baseErr)
-	var targetErr *customErr
 
 	expect.Error(t, errors.New("something went wrong"))
 	expect.NoError(t, nil)
 	expect.ErrorIs(t, wrappedErr, baseErr)
 	expect.NotErrorIs(t, errors.New("other"), baseErr)
+
+	if custom, ok := expect.ErrorAsType[*customErr](t, fmt.Errorf("wrapped custom: %w", &customErr{})); ok {
+		expect.Equal(t, "custom", custom.Error())
+	}
+
+	var targetErr *customErr
 	expect.ErrorAs(t, fmt.Errorf("wrapped custom: %w", &customErr{}), &targetErr)
 }
 
+func TestErrorMessages(t *testing.T) {
+	err := fmt.Errorf("request failed: %w", errors.New("timeout"))
+
+	expect.EqualError(t, err, "request failed: timeout")
+	expect.ErrorContains(t, err, "timeout")
+	expect.ErrorMatches(t, err, `^request failed: \w+$`)
+}
+
+func TestErrorTrees(t *testing.T) {
+	errNotFound := errors.New("not found")
+	errDenied := errors.New("permission denied")
+	err := fmt.Errorf("sync: %w", errors.Join(errNotFound, errDenied))
+
+	expect.ErrorIsAll(t, err, errNotFound)
+	expect.ErrorIsExactly(t, err, errNotFound, errDenied)
+}
+
+func TestTypes(t *testing.T) {
+	var value any = &customErr{}
+
+	if custom, ok := expect.IsType[*customErr](t, value); ok {
+		expect.Equal(t, "custom", custom.Error())
+	}
+	if err, ok := expect.Implements[error](t, value); ok {
+		expect.EqualError(t, err, "custom")
+	}
+	expect.ImplementsType(t, value, reflect.TypeFor[error]())
+	expect.AssignableTo(t, value, reflect.TypeFor[error]())
+}
+
 func TestSlices(t *testing.T) {
 	expect.EqualSlice(t, []int{1, 2, 3}, []int{1, 2, 3})
 	expect.NotEqualSlice(t, []int{1, 2, 3}, []int{4, 5, 6})
@@ -60,6 +172,255 @@ func TestSlices(t *testing.T) {
 	expect.EqualSlice(t, []string{"a", "b"}, []string{"a", "b"})
 }
 
+func TestQuantifiers(t *testing.T) {
+	positive := func(n int) bool { return n > 0 }
+	expect.AllSlice(t, []int{1, 2, 3}, positive)
+	expect.AnySlice(t, []int{-1, 0, 1}, positive)
+	expect.NoneSlice(t, []int{-2, -1}, positive)
+	expect.ExactlyNSlice(t, []int{-1, 1, 2}, 2, positive)
+
+	stock := map[string]int{"apples": 3, "pears": 0}
+	expect.AllMap(t, stock, func(_ string, n int) bool { return n >= 0 })
+	expect.AnyMap(t, stock, func(_ string, n int) bool { return n == 0 })
+	expect.NoneMap(t, stock, func(name string, _ int) bool { return name == "" })
+	expect.ExactlyNMap(t, stock, 1, func(_ string, n int) bool { return n > 0 })
+}
+
+func TestSequences(t *testing.T) {
+	stock := map[string]int{"apples": 3, "pears": 0}
+	expect.ElementsMatchSeq(t, []string{"pears", "apples"}, maps.Keys(stock))
+	expect.ContainsSeq(t, maps.Values(stock), 3)
+	expect.LenSeq2(t, maps.All(stock), 2)
+
+	expect.EqualSeq(t, []string{"apples", "pears"}, slices.Values(slices.Sorted(maps.Keys(stock))))
+	expect.EmptySeq(t, maps.Keys(map[string]int{}))
+
+	evens := func(yield func(int) bool) {
+		for i := 0; ; i += 2 {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	expect.ContainsSeq(t, evens, 10) // stops consuming at the first match
+	expect.NotEmptySeq(t, evens)
+}
+
+func TestSequenceConformance(t *testing.T) {
+	open := 0
+	countdown := func(n int) iter.Seq[int] {
+		return func(yield func(int) bool) {
+			open++
+			defer func() { open-- }()
+			for i := n; i > 0; i-- {
+				if !yield(i) {
+					return
+				}
+			}
+		}
+	}
+
+	expect.ConformsSeq(t, []int{3, 2, 1}, func() iter.Seq[int] { return countdown(3) })
+	expect.ReleasesSeq(t, func() iter.Seq[int] { return countdown(3) }, func() int { return open })
+}
+
+func TestChannels(t *testing.T) {
+	double := func(in <-chan int) <-chan int {
+		out := make(chan int)
+		go func() {
+			defer close(out)
+			for v := range in {
+				out <- v * 2
+			}
+		}()
+		return out
+	}
+
+	in := make(chan int)
+	out := double(in)
+	expect.NotReceives(t, out, 10*time.Millisecond)
+
+	in <- 1
+	expect.ReceivesValue(t, out, 2, time.Second)
+	in <- 2
+	if v, ok := expect.Receives(t, out, time.Second); ok {
+		expect.Equal(t, 4, v)
+	}
+	expect.NotClosed(t, out)
+
+	go func() {
+		in <- 3
+		close(in)
+	}()
+	expect.DrainsTo(t, out, []int{6}, time.Second)
+	expect.Closed(t, out, time.Second)
+}
+
+func TestCompletion(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Go(func() { time.Sleep(time.Millisecond) })
+	expect.CompletesWithin(t, time.Second, wg.Wait)
+
+	var mu sync.Mutex
+	mu.Lock()
+	expect.Blocks(t, 10*time.Millisecond, mu.Lock)
+	mu.Unlock()
+}
+
+func TestEventually(t *testing.T) {
+	debounce := func(d time.Duration, fn func()) func() {
+		var mu sync.Mutex
+		var timer *time.Timer
+		return func() {
+			mu.Lock()
+			defer mu.Unlock()
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.AfterFunc(d, fn)
+		}
+	}
+
+	check := func(t *testing.T, d time.Duration) {
+		var mu sync.Mutex
+		calls := 0
+		called := func() int {
+			mu.Lock()
+			defer mu.Unlock()
+			return calls
+		}
+		trigger := debounce(d, func() {
+			mu.Lock()
+			defer mu.Unlock()
+			calls++
+		})
+
+		trigger()
+		trigger()
+		expect.Consistently(t, func() bool { return called() == 0 }, d/2, d/10)
+		expect.Eventually(t, func() bool { return called() == 1 }, 2*d, d/10)
+
+		fired := time.After(d)
+		expect.NotReceives(t, fired, d/2)
+		expect.Receives(t, fired, d)
+	}
+
+	// Outside a bubble, the assertions sleep in real time.
+	check(t, 50*time.Millisecond)
+
+	// Inside a bubble, time is fake: an hour passes instantly, and every
+	// check runs once the bubble's goroutines are blocked.
+	synctest.Test(t, func(t *testing.T) {
+		check(t, time.Hour)
+	})
+}
+
+func TestConcurrentAssertions(t *testing.T) {
+	ct := expect.Concurrent(t)
+	squares := make([]int, 8)
+	var wg sync.WaitGroup
+	for i := range squares {
+		wg.Go(func() {
+			squares[i] = i * i
+			expect.Equal(ct, i*i, squares[i])
+			expect.GreaterOrEqual(ct, squares[i], i)
+		})
+	}
+	wg.Wait()
+	ct.Flush()
+}
+
+func TestStress(t *testing.T) {
+	var mu sync.RWMutex
+	cache := map[int]string{}
+	const capacity = 16
+
+	expect.Stress(t, expect.StressConfig{
+		Goroutines: 4,
+		Rounds:     5,
+		Jitter:     true,
+		Invariant: func(t expect.T) {
+			expect.LessOrEqual(t, len(cache), capacity)
+		},
+	},
+		expect.StressOp{Name: "Put", Run: func(t expect.T, r *rand.Rand) {
+			mu.Lock()
+			defer mu.Unlock()
+			cache[r.IntN(capacity)] = "v"
+		}},
+		expect.StressOp{Name: "Get", Run: func(t expect.T, r *rand.Rand) {
+			mu.RLock()
+			defer mu.RUnlock()
+			if v, ok := cache[r.IntN(capacity)]; ok {
+				expect.Equal(t, "v", v)
+			}
+		}},
+	)
+}
+
+type counterOp struct{ add int }
+
+func (op counterOp) String() string { return fmt.Sprintf("Add(%d)", op.add) }
+
+func TestLinearizable(t *testing.T) {
+	// The model of a counter whose Add returns the new total.
+	model := expect.Model[int, counterOp, int]{
+		Init: func() int { return 0 },
+		Step: func(total int, op counterOp) (int, int) { return total + op.add, total + op.add },
+	}
+
+	var mu sync.Mutex
+	total := 0
+	add := func(n int) int {
+		mu.Lock()
+		defer mu.Unlock()
+		total += n
+		return total
+	}
+
+	history := &expect.History[counterOp, int]{}
+	expect.Stress(t, expect.StressConfig{Goroutines: 4, Iterations: 25},
+		expect.StressOp{Name: "Add", Run: func(t expect.T, r *rand.Rand) {
+			n := r.IntN(3)
+			ret := history.Call(counterOp{n})
+			ret(add(n))
+		}},
+	)
+	expect.Linearizable(t, model, history)
+}
+
+func TestPerformance(t *testing.T) {
+	buf := make([]byte, 0, 64)
+	expect.AllocsAtMost(t, 0, func() { buf = strconv.AppendInt(buf[:0], 42, 10) })
+	expect.BytesAllocatedAtMost(t, 1024, func() { buf = fmt.Appendf(buf[:0], "%d", 42) })
+	expect.DurationAtMost(t, 10*time.Millisecond, func() { slices.Sort([]int{3, 1, 2}) })
+}
+
+func BenchmarkPerformance(b *testing.B) {
+	hits := 0
+	cache := map[int]int{1: 1}
+	for b.Loop() {
+		if _, ok := cache[1]; ok {
+			hits++
+		}
+	}
+	expect.NsPerOpAtMost(b, time.Millisecond)
+	expect.MetricAtLeast(b, float64(hits)/float64(b.N), "hits/op", 1)
+}
+
+func TestNoGoroutineLeaks(t *testing.T) {
+	expect.NoGoroutineLeaks(t)
+
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		<-quit
+	}()
+	close(quit)
+	<-done
+}
+
 func TestContainsString(t *testing.T) {
 	expect.ContainsString(t, "hello world", "world")
 	expect.NotContainsString(t, "hello world", "golang")
@@ -70,12 +431,27 @@ func TestMaps(t *testing.T) {
 	expect.NotEqualMap(t, map[string]int{"a": 1}, map[string]int{"a": 2})
 	expect.ContainsMapKey(t, map[string]int{"a": 1}, "a")
 	expect.NotContainsMapKey(t, map[string]int{"a": 1}, "b")
+	expect.ContainsMapEntry(t, map[string]int{"a": 1}, "a", 1)
+	expect.ContainsMapValue(t, map[string]int{"a": 1}, 1)
+	expect.NotContainsMapValue(t, map[string]int{"a": 1}, 2)
 }
 
 func TestLengthAndEmpty(t *testing.T) {
 	expect.Len(t, []int{1, 2, 3}, 3)
 	expect.Empty(t, "")
 	expect.NotEmpty(t, "hello")
+	expect.Empty(t, struct{ Name string }{})
+}
+
+func TestZeroAndPointers(t *testing.T) {
+	expect.Zero(t, 0)
+	expect.NotZero(t, struct{ ID int }{ID: 1})
+
+	value := 42
+	ptr := &value
+	expect.Same(t, ptr, &value)
+	expect.NotSame(t, ptr, new(int))
+	expect.PointsTo(t, 42, ptr)
 }
 
 type customErr struct{}
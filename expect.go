@@ -4,6 +4,7 @@ package expect
 import (
 	"cmp"
 	"errors"
+	"fmt"
 	"maps"
 	"reflect"
 	"slices"
@@ -13,6 +14,10 @@ import (
 var errorType = reflect.TypeFor[error]()
 
 // T is the interface required for test assertions. Both *testing.T and *testing.B satisfy it.
+//
+// If the value passed as T also has an Attr(key, value string) method, as
+// *testing.T does since Go 1.25, failures additionally emit structured test
+// attributes. See [AttrAssertion] for details.
 type T interface {
 	Helper()
 	Errorf(format string, args ...any)
@@ -22,7 +27,7 @@ type T interface {
 func Equal[V comparable](t T, expected, actual V) {
 	t.Helper()
 	if expected != actual {
-		failMismatch(t, expected, actual)
+		failMismatch(t, "Equal", expected, actual)
 	}
 }
 
@@ -30,7 +35,16 @@ func Equal[V comparable](t T, expected, actual V) {
 func NotEqual[V comparable](t T, unexpected, actual V) {
 	t.Helper()
 	if unexpected == actual {
-		failMatch(t, actual)
+		failMatch(t, "NotEqual", actual)
+	}
+}
+
+// EqualFunc asserts that expected and actual are equal according to eq.
+// Method expressions such as time.Time.Equal can be passed as eq.
+func EqualFunc[V any](t T, expected, actual V, eq func(V, V) bool) {
+	t.Helper()
+	if !eq(expected, actual) {
+		failMismatch(t, "EqualFunc", expected, actual)
 	}
 }
 
@@ -38,7 +52,7 @@ func NotEqual[V comparable](t T, unexpected, actual V) {
 func Less[V cmp.Ordered](t T, a, b V) {
 	t.Helper()
 	if a >= b {
-		failCompare(t, a, "<", b)
+		failCompare(t, "Less", a, "<", b)
 	}
 }
 
@@ -46,7 +60,7 @@ func Less[V cmp.Ordered](t T, a, b V) {
 func LessOrEqual[V cmp.Ordered](t T, a, b V) {
 	t.Helper()
 	if a > b {
-		failCompare(t, a, "<=", b)
+		failCompare(t, "LessOrEqual", a, "<=", b)
 	}
 }
 
@@ -54,7 +68,7 @@ func LessOrEqual[V cmp.Ordered](t T, a, b V) {
 func Greater[V cmp.Ordered](t T, a, b V) {
 	t.Helper()
 	if a <= b {
-		failCompare(t, a, ">", b)
+		failCompare(t, "Greater", a, ">", b)
 	}
 }
 
@@ -62,7 +76,41 @@ func Greater[V cmp.Ordered](t T, a, b V) {
 func GreaterOrEqual[V cmp.Ordered](t T, a, b V) {
 	t.Helper()
 	if a < b {
-		failCompare(t, a, ">=", b)
+		failCompare(t, "GreaterOrEqual", a, ">=", b)
+	}
+}
+
+// LessFunc asserts that a < b according to compare, which returns a negative
+// number, zero or a positive number like [cmp.Compare]. Method expressions
+// such as time.Time.Compare can be passed as compare.
+func LessFunc[V any](t T, a, b V, compare func(V, V) int) {
+	t.Helper()
+	if compare(a, b) >= 0 {
+		failCompare(t, "LessFunc", a, "<", b)
+	}
+}
+
+// LessOrEqualFunc asserts that a <= b according to compare.
+func LessOrEqualFunc[V any](t T, a, b V, compare func(V, V) int) {
+	t.Helper()
+	if compare(a, b) > 0 {
+		failCompare(t, "LessOrEqualFunc", a, "<=", b)
+	}
+}
+
+// GreaterFunc asserts that a > b according to compare.
+func GreaterFunc[V any](t T, a, b V, compare func(V, V) int) {
+	t.Helper()
+	if compare(a, b) <= 0 {
+		failCompare(t, "GreaterFunc", a, ">", b)
+	}
+}
+
+// GreaterOrEqualFunc asserts that a >= b according to compare.
+func GreaterOrEqualFunc[V any](t T, a, b V, compare func(V, V) int) {
+	t.Helper()
+	if compare(a, b) < 0 {
+		failCompare(t, "GreaterOrEqualFunc", a, ">=", b)
 	}
 }
 
@@ -70,7 +118,7 @@ func GreaterOrEqual[V cmp.Ordered](t T, a, b V) {
 func True(t T, value bool) {
 	t.Helper()
 	if !value {
-		failMismatch(t, true, false)
+		failMismatch(t, "True", true, false)
 	}
 }
 
@@ -78,7 +126,7 @@ func True(t T, value bool) {
 func False(t T, value bool) {
 	t.Helper()
 	if value {
-		failMismatch(t, false, true)
+		failMismatch(t, "False", false, true)
 	}
 }
 
@@ -86,7 +134,7 @@ func False(t T, value bool) {
 func Nil(t T, value any) {
 	t.Helper()
 	if !isNil(value) {
-		t.Errorf("expected nil, got %v (%T)", value, value)
+		errorfValues(t, "Nil", nil, value, "expected nil, got %v (%T)", value, value)
 	}
 }
 
@@ -94,7 +142,37 @@ func Nil(t T, value any) {
 func NotNil(t T, value any) {
 	t.Helper()
 	if isNil(value) {
-		t.Errorf("expected non-nil value, got nil (%T)", value)
+		errorfActual(t, "NotNil", value, "expected non-nil value, got nil (%T)", value)
+	}
+}
+
+// Same asserts that expected and actual point to the same variable.
+func Same[P any](t T, expected, actual *P) {
+	t.Helper()
+	if expected != actual {
+		errorfValues(t, "Same", fmt.Sprintf("%p", expected), fmt.Sprintf("%p", actual), "expected pointer %p, got %p", expected, actual)
+	}
+}
+
+// NotSame asserts that unexpected and actual do not point to the same
+// variable.
+func NotSame[P any](t T, unexpected, actual *P) {
+	t.Helper()
+	if unexpected == actual {
+		errorf(t, "NotSame", "expected different pointers, got %p for both", actual)
+	}
+}
+
+// PointsTo asserts that ptr is not nil and the value it points to equals
+// expected.
+func PointsTo[V comparable](t T, expected V, ptr *V) {
+	t.Helper()
+	if ptr == nil {
+		errorfValues(t, "PointsTo", expected, nil, "expected pointer to %v, got nil", expected)
+		return
+	}
+	if *ptr != expected {
+		errorfValues(t, "PointsTo", expected, *ptr, "expected pointer to %v, got pointer to %v", expected, *ptr)
 	}
 }
 
@@ -117,7 +195,7 @@ func isNil(value any) bool {
 func Error(t T, err error) {
 	t.Helper()
 	if err == nil {
-		t.Errorf("expected an error, got nil")
+		errorf(t, "Error", "expected an error, got nil")
 	}
 }
 
@@ -125,7 +203,7 @@ func Error(t T, err error) {
 func NoError(t T, err error) {
 	t.Helper()
 	if err != nil {
-		t.Errorf("expected no error, got %v", err)
+		errorfActual(t, "NoError", err, "expected no error, got %v", err)
 	}
 }
 
@@ -133,7 +211,7 @@ func NoError(t T, err error) {
 func ErrorIs(t T, err, target error) {
 	t.Helper()
 	if !errors.Is(err, target) {
-		t.Errorf("expected error %v to match %v", err, target)
+		errorf(t, "ErrorIs", "expected error %v to match %v\n%s", err, target, errorTree(err))
 	}
 }
 
@@ -141,35 +219,55 @@ func ErrorIs(t T, err, target error) {
 func NotErrorIs(t T, err, target error) {
 	t.Helper()
 	if errors.Is(err, target) {
-		t.Errorf("expected error %v not to match %v", err, target)
+		errorf(t, "NotErrorIs", "expected error %v not to match %v\n%s", err, target, errorTree(err))
 	}
 }
 
 // ErrorAs asserts that err matches target using errors.As.
+//
+// Most callers should use [ErrorAsType], which checks the target type at
+// compile time and returns the matched error.
 func ErrorAs(t T, err error, target any) {
 	t.Helper()
 	v := reflect.ValueOf(target)
 	if target == nil || v.Kind() != reflect.Pointer || v.IsNil() {
-		t.Errorf("expected target to be a non-nil pointer, got %T", target)
+		errorf(t, "ErrorAs", "expected target to be a non-nil pointer, got %T", target)
 		return
 	}
 
 	typeToMatch := v.Elem().Type()
 	if !typeToMatch.Implements(errorType) && typeToMatch.Kind() != reflect.Interface {
-		t.Errorf("expected target to point to an error or interface type, got %T", target)
+		errorf(t, "ErrorAs", "expected target to point to an error or interface type, got %T", target)
 		return
 	}
 
 	if !errors.As(err, target) {
-		t.Errorf("expected error %v to match target type %v", err, typeToMatch)
+		errorf(t, "ErrorAs", "expected error %v to match target type %v\n%s", err, typeToMatch, errorTree(err))
+	}
+}
+
+// ErrorAsType asserts that err matches the error type E using errors.As, and
+// returns the matched error for further assertions. E may be a concrete
+// error type or an interface that embeds error.
+//
+//	if pathErr, ok := expect.ErrorAsType[*fs.PathError](t, err); ok {
+//		expect.Equal(t, "config.json", pathErr.Path)
+//	}
+func ErrorAsType[E error](t T, err error) (E, bool) {
+	t.Helper()
+	var target E
+	if !errors.As(err, &target) {
+		errorf(t, "ErrorAsType", "expected error %v to match type %v\n%s", err, reflect.TypeFor[E](), errorTree(err))
+		return target, false
 	}
+	return target, true
 }
 
 // EqualSlice asserts that expected and actual slices are equal.
 func EqualSlice[S ~[]E, E comparable](t T, expected, actual S) {
 	t.Helper()
 	if !slices.Equal(expected, actual) {
-		failMismatch(t, expected, actual)
+		failMismatch(t, "EqualSlice", expected, actual)
 	}
 }
 
@@ -177,7 +275,16 @@ func EqualSlice[S ~[]E, E comparable](t T, expected, actual S) {
 func NotEqualSlice[S ~[]E, E comparable](t T, unexpected, actual S) {
 	t.Helper()
 	if slices.Equal(unexpected, actual) {
-		failMatch(t, actual)
+		failMatch(t, "NotEqualSlice", actual)
+	}
+}
+
+// EqualSliceFunc asserts that expected and actual slices have the same length
+// and that eq reports each pair of elements as equal.
+func EqualSliceFunc[S ~[]E, E any](t T, expected, actual S, eq func(E, E) bool) {
+	t.Helper()
+	if !slices.EqualFunc(expected, actual, eq) {
+		failMismatch(t, "EqualSliceFunc", expected, actual)
 	}
 }
 
@@ -185,7 +292,7 @@ func NotEqualSlice[S ~[]E, E comparable](t T, unexpected, actual S) {
 func ContainsSlice[S ~[]E, E comparable](t T, values S, item E) {
 	t.Helper()
 	if !slices.Contains(values, item) {
-		t.Errorf("expected %v to contain %v", values, item)
+		errorf(t, "ContainsSlice", "expected %v to contain %v", values, item)
 	}
 }
 
@@ -193,7 +300,7 @@ func ContainsSlice[S ~[]E, E comparable](t T, values S, item E) {
 func NotContainsSlice[S ~[]E, E comparable](t T, values S, item E) {
 	t.Helper()
 	if slices.Contains(values, item) {
-		t.Errorf("expected %v not to contain %v", values, item)
+		errorf(t, "NotContainsSlice", "expected %v not to contain %v", values, item)
 	}
 }
 
@@ -201,7 +308,7 @@ func NotContainsSlice[S ~[]E, E comparable](t T, values S, item E) {
 func ContainsString(t T, s, substr string) {
 	t.Helper()
 	if !strings.Contains(s, substr) {
-		t.Errorf("expected %q to contain %q", s, substr)
+		errorf(t, "ContainsString", "expected %q to contain %q", s, substr)
 	}
 }
 
@@ -209,7 +316,7 @@ func ContainsString(t T, s, substr string) {
 func NotContainsString(t T, s, substr string) {
 	t.Helper()
 	if strings.Contains(s, substr) {
-		t.Errorf("expected %q not to contain %q", s, substr)
+		errorf(t, "NotContainsString", "expected %q not to contain %q", s, substr)
 	}
 }
 
@@ -218,48 +325,61 @@ func Len(t T, value any, expected int) {
 	t.Helper()
 	actual, ok := valueLen(value)
 	if !ok {
-		t.Errorf("expected value with length, got %T", value)
+		errorf(t, "Len", "expected value with length, got %T", value)
 		return
 	}
 	if actual != expected {
-		t.Errorf("expected length %d, got %d", expected, actual)
+		errorfValues(t, "Len", expected, actual, "expected length %d, got %d", expected, actual)
 	}
 }
 
-// Empty asserts that value is empty.
+// Empty asserts that value is empty. Nil values, zero-length strings,
+// arrays, slices and maps, channels without buffered elements (open or closed) and
+// zero values of any other type are empty. A non-nil pointer is empty when
+// the value it points to is.
 func Empty(t T, value any) {
 	t.Helper()
-	if isNil(value) {
-		return
-	}
-
-	length, ok := valueLen(value)
-	if !ok {
-		t.Errorf("expected empty value, got unsupported type %T", value)
-		return
-	}
-
-	if length != 0 {
-		t.Errorf("expected empty value, got %v", value)
+	if !isEmpty(value) {
+		errorfActual(t, "Empty", value, "expected empty value, got %v", value)
 	}
 }
 
-// NotEmpty asserts that value is not empty.
+// NotEmpty asserts that value is not empty, as defined by [Empty].
 func NotEmpty(t T, value any) {
 	t.Helper()
+	if isEmpty(value) {
+		errorfActual(t, "NotEmpty", value, "expected non-empty value, got %v (%T)", value, value)
+	}
+}
+
+func isEmpty(value any) bool {
 	if isNil(value) {
-		t.Errorf("expected non-empty value, got %v", value)
-		return
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Pointer:
+		return isEmpty(v.Elem().Interface())
+	case reflect.Array, reflect.Chan, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
 	}
+}
 
-	length, ok := valueLen(value)
-	if !ok {
-		t.Errorf("expected non-empty value, got unsupported type %T", value)
-		return
+// Zero asserts that value is nil or the zero value of its type.
+func Zero(t T, value any) {
+	t.Helper()
+	if value != nil && !reflect.ValueOf(value).IsZero() {
+		errorfValues(t, "Zero", reflect.Zero(reflect.TypeOf(value)).Interface(), value, "expected zero value, got %v (%T)", value, value)
 	}
+}
 
-	if length == 0 {
-		t.Errorf("expected non-empty value, got %v", value)
+// NotZero asserts that value is not nil and not the zero value of its type.
+func NotZero(t T, value any) {
+	t.Helper()
+	if value == nil || reflect.ValueOf(value).IsZero() {
+		errorfActual(t, "NotZero", value, "expected non-zero value, got %v (%T)", value, value)
 	}
 }
 
@@ -267,7 +387,7 @@ func NotEmpty(t T, value any) {
 func EqualMap[M ~map[K]V, K, V comparable](t T, expected, actual M) {
 	t.Helper()
 	if !maps.Equal(expected, actual) {
-		failMismatch(t, expected, actual)
+		failMapMismatch(t, "EqualMap", expected, actual, func(a, b V) bool { return a == b })
 	}
 }
 
@@ -275,7 +395,16 @@ func EqualMap[M ~map[K]V, K, V comparable](t T, expected, actual M) {
 func NotEqualMap[M ~map[K]V, K, V comparable](t T, unexpected, actual M) {
 	t.Helper()
 	if maps.Equal(unexpected, actual) {
-		failMatch(t, actual)
+		failMatch(t, "NotEqualMap", actual)
+	}
+}
+
+// EqualMapFunc asserts that expected and actual maps have the same keys and
+// that eq reports the values for each key as equal.
+func EqualMapFunc[M ~map[K]V, K comparable, V any](t T, expected, actual M, eq func(V, V) bool) {
+	t.Helper()
+	if !maps.EqualFunc(expected, actual, eq) {
+		failMapMismatch(t, "EqualMapFunc", expected, actual, eq)
 	}
 }
 
@@ -283,7 +412,7 @@ func NotEqualMap[M ~map[K]V, K, V comparable](t T, unexpected, actual M) {
 func ContainsMapKey[M ~map[K]V, K comparable, V any](t T, m M, key K) {
 	t.Helper()
 	if _, ok := m[key]; !ok {
-		t.Errorf("expected map %v to contain key %v", m, key)
+		errorf(t, "ContainsMapKey", "expected map %v to contain key %v", m, key)
 	}
 }
 
@@ -291,7 +420,39 @@ func ContainsMapKey[M ~map[K]V, K comparable, V any](t T, m M, key K) {
 func NotContainsMapKey[M ~map[K]V, K comparable, V any](t T, m M, key K) {
 	t.Helper()
 	if _, ok := m[key]; ok {
-		t.Errorf("expected map %v not to contain key %v", m, key)
+		errorf(t, "NotContainsMapKey", "expected map %v not to contain key %v", m, key)
+	}
+}
+
+// ContainsMapEntry asserts that m contains key with value.
+func ContainsMapEntry[M ~map[K]V, K, V comparable](t T, m M, key K, value V) {
+	t.Helper()
+	actual, ok := m[key]
+	switch {
+	case !ok:
+		errorfValues(t, "ContainsMapEntry", value, nil, "expected map %v to contain key %v", m, key)
+	case actual != value:
+		errorfValues(t, "ContainsMapEntry", value, actual, "expected map %v to contain %v: %v, got %v: %v", m, key, value, key, actual)
+	}
+}
+
+// ContainsMapValue asserts that m contains value under any key.
+func ContainsMapValue[M ~map[K]V, K, V comparable](t T, m M, value V) {
+	t.Helper()
+	for _, v := range m {
+		if v == value {
+			return
+		}
+	}
+	errorf(t, "ContainsMapValue", "expected map %v to contain value %v", m, value)
+}
+
+// NotContainsMapValue asserts that m does not contain value under any key.
+func NotContainsMapValue[M ~map[K]V, K, V comparable](t T, m M, value V) {
+	t.Helper()
+	keys := mapKeysFunc(m, func(_ K, v V) bool { return v == value })
+	if len(keys) > 0 {
+		errorf(t, "NotContainsMapValue", "expected map %v not to contain value %v, found at keys %v", m, value, keys)
 	}
 }
 
@@ -305,17 +466,61 @@ func valueLen(value any) (int, bool) {
 	}
 }
 
-func failMismatch(t T, expected, actual any) {
+func failMismatch(t T, assertion string, expected, actual any) {
 	t.Helper()
-	t.Errorf("expected %v, got %v", expected, actual)
+	errorfValues(t, assertion, expected, actual, "expected %v, got %v", expected, actual)
 }
 
-func failMatch(t T, value any) {
+// failMapMismatch reports unequal maps, listing missing keys, extra keys and
+// changed values separately in key order.
+func failMapMismatch[M ~map[K]V, K comparable, V any](t T, assertion string, expected, actual M, eq func(V, V) bool) {
+	t.Helper()
+	var missing, extra, changed []K
+	for k, v := range expected {
+		a, ok := actual[k]
+		switch {
+		case !ok:
+			missing = append(missing, k)
+		case !eq(v, a):
+			changed = append(changed, k)
+		}
+	}
+	for k := range actual {
+		if _, ok := expected[k]; !ok {
+			extra = append(extra, k)
+		}
+	}
+
+	var b strings.Builder
+	if len(missing) > 0 {
+		sortKeys(missing)
+		b.WriteString("\nmissing keys:" + formatEntries(expected, missing))
+	}
+	if len(extra) > 0 {
+		sortKeys(extra)
+		b.WriteString("\nextra keys:" + formatEntries(actual, extra))
+	}
+	if len(changed) > 0 {
+		sortKeys(changed)
+		b.WriteString("\nchanged values:")
+		for n, k := range changed {
+			if n == maxDifferences {
+				fmt.Fprintf(&b, "\n  ... and %d more", len(changed)-n)
+				break
+			}
+			fmt.Fprintf(&b, "\n  [%s]: expected %v, got %v", formatValue(keyValue(k)), expected[k], actual[k])
+		}
+	}
+	errorfValues(t, assertion, expected, actual, "expected %v, got %v%s", expected, actual, b.String())
+}
+
+func failMatch(t T, assertion string, value any) {
 	t.Helper()
+	emitAttrs(t, assertion, AttrActual, compact(value))
 	t.Errorf("expected different values, got equal: %v", value)
 }
 
-func failCompare(t T, a any, op string, b any) {
+func failCompare(t T, assertion string, a any, op string, b any) {
 	t.Helper()
-	t.Errorf("expected %v %s %v", a, op, b)
+	errorfValues(t, assertion, b, a, "expected %v %s %v", a, op, b)
 }
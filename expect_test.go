@@ -3,11 +3,15 @@ package expect
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 type mockT struct {
-	failed bool
+	failed  bool
+	message string
 }
 
 type customError struct{}
@@ -20,6 +24,7 @@ func (m *mockT) Helper() {}
 
 func (m *mockT) Errorf(format string, args ...any) {
 	m.failed = true
+	m.message = fmt.Sprintf(format, args...)
 }
 
 func TestEqual(t *testing.T) {
@@ -146,6 +151,63 @@ func TestGreaterOrEqual(t *testing.T) {
 	})
 }
 
+func TestEqualFunc(t *testing.T) {
+	instant := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		EqualFunc(m, instant, instant.In(time.FixedZone("X", 3600)), time.Time.Equal)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		EqualFunc(m, instant, instant.Add(time.Second), time.Time.Equal)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestOrderingFuncs(t *testing.T) {
+	early := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := early.Add(time.Hour)
+
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		LessFunc(m, early, late, time.Time.Compare)
+		LessOrEqualFunc(m, early, early, time.Time.Compare)
+		GreaterFunc(m, late, early, time.Time.Compare)
+		GreaterOrEqualFunc(m, late, late, time.Time.Compare)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	tests := []struct {
+		name   string
+		assert func(T)
+	}{
+		{"LessFunc", func(m T) { LessFunc(m, late, early, time.Time.Compare) }},
+		{"LessFunc equal", func(m T) { LessFunc(m, early, early, time.Time.Compare) }},
+		{"LessOrEqualFunc", func(m T) { LessOrEqualFunc(m, late, early, time.Time.Compare) }},
+		{"GreaterFunc", func(m T) { GreaterFunc(m, early, late, time.Time.Compare) }},
+		{"GreaterFunc equal", func(m T) { GreaterFunc(m, late, late, time.Time.Compare) }},
+		{"GreaterOrEqualFunc", func(m T) { GreaterOrEqualFunc(m, early, late, time.Time.Compare) }},
+	}
+	for _, tt := range tests {
+		t.Run("fail "+tt.name, func(t *testing.T) {
+			m := &mockT{}
+			tt.assert(m)
+			if !m.failed {
+				t.Error("expected fail")
+			}
+		})
+	}
+}
+
 func TestTrue(t *testing.T) {
 	t.Run("pass", func(t *testing.T) {
 		m := &mockT{}
@@ -449,6 +511,59 @@ func TestErrorAs(t *testing.T) {
 	})
 }
 
+type timeoutError interface {
+	error
+	Timeout() bool
+}
+
+type netError struct{}
+
+func (netError) Error() string { return "net" }
+func (netError) Timeout() bool { return true }
+
+func TestErrorAsType(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		target := &customError{}
+		got, ok := ErrorAsType[*customError](m, fmt.Errorf("wrapped: %w", target))
+		if m.failed || !ok {
+			t.Error("expected pass")
+		}
+		if got != target {
+			t.Errorf("expected matched error %p, got %p", target, got)
+		}
+	})
+
+	t.Run("pass interface", func(t *testing.T) {
+		m := &mockT{}
+		got, ok := ErrorAsType[timeoutError](m, fmt.Errorf("wrapped: %w", netError{}))
+		if m.failed || !ok {
+			t.Error("expected pass")
+		}
+		if got == nil || !got.Timeout() {
+			t.Errorf("expected timeout error, got %v", got)
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		got, ok := ErrorAsType[*customError](m, errors.New("different"))
+		if !m.failed || ok {
+			t.Error("expected fail")
+		}
+		if got != nil {
+			t.Errorf("expected nil, got %v", got)
+		}
+	})
+
+	t.Run("fail nil", func(t *testing.T) {
+		m := &mockT{}
+		if _, ok := ErrorAsType[*customError](m, nil); !m.failed || ok {
+			t.Error("expected fail")
+		}
+	})
+}
+
 func TestEqualSlice(t *testing.T) {
 	t.Run("pass", func(t *testing.T) {
 		m := &mockT{}
@@ -485,6 +600,24 @@ func TestNotEqualSlice(t *testing.T) {
 	})
 }
 
+func TestEqualSliceFunc(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		EqualSliceFunc(m, []string{"Go", "GOPHER"}, []string{"go", "gopher"}, strings.EqualFold)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		EqualSliceFunc(m, []string{"go"}, []string{"go", "gopher"}, strings.EqualFold)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
 func TestContainsSlice(t *testing.T) {
 	t.Run("pass", func(t *testing.T) {
 		m := &mockT{}
@@ -641,13 +774,66 @@ func TestEmpty(t *testing.T) {
 		}
 	})
 
-	t.Run("fail unsupported", func(t *testing.T) {
+	t.Run("fail zero array", func(t *testing.T) {
+		m := &mockT{}
+		Empty(m, [2]int{})
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("pass zero values", func(t *testing.T) {
+		m := &mockT{}
+		Empty(m, 0)
+		Empty(m, false)
+		Empty(m, struct{ A, B int }{})
+		Empty(m, new(string))
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("pass channels", func(t *testing.T) {
+		m := &mockT{}
+		Empty(m, make(chan int))
+		drained := make(chan int, 1)
+		drained <- 1
+		<-drained
+		Empty(m, drained)
+		closed := make(chan int)
+		close(closed)
+		Empty(m, closed)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail non-zero", func(t *testing.T) {
 		m := &mockT{}
 		Empty(m, 10)
 		if !m.failed {
 			t.Error("expected fail")
 		}
 	})
+
+	t.Run("fail non-zero struct", func(t *testing.T) {
+		m := &mockT{}
+		Empty(m, &struct{ A int }{A: 1})
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail buffered channel", func(t *testing.T) {
+		m := &mockT{}
+		ch := make(chan int, 1)
+		ch <- 1
+		close(ch)
+		Empty(m, ch)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
 }
 
 func TestNotEmpty(t *testing.T) {
@@ -675,6 +861,14 @@ func TestNotEmpty(t *testing.T) {
 		}
 	})
 
+	t.Run("pass zero array", func(t *testing.T) {
+		m := &mockT{}
+		NotEmpty(m, [3]int{})
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
 	t.Run("fail empty", func(t *testing.T) {
 		m := &mockT{}
 		NotEmpty(m, "")
@@ -691,9 +885,163 @@ func TestNotEmpty(t *testing.T) {
 		}
 	})
 
-	t.Run("fail unsupported", func(t *testing.T) {
+	t.Run("pass non-zero values", func(t *testing.T) {
 		m := &mockT{}
 		NotEmpty(m, 10)
+		NotEmpty(m, true)
+		NotEmpty(m, struct{ A int }{A: 1})
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail zero", func(t *testing.T) {
+		m := &mockT{}
+		NotEmpty(m, 0)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail zero struct", func(t *testing.T) {
+		m := &mockT{}
+		NotEmpty(m, struct{ A int }{})
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail drained channel", func(t *testing.T) {
+		m := &mockT{}
+		ch := make(chan int)
+		close(ch)
+		NotEmpty(m, ch)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestZero(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		Zero(m, nil)
+		Zero(m, 0)
+		Zero(m, "")
+		Zero(m, struct{ A []int }{})
+		Zero(m, (*int)(nil))
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		Zero(m, struct{ A int }{A: 1})
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail empty slice", func(t *testing.T) {
+		m := &mockT{}
+		Zero(m, []int{})
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestNotZero(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		NotZero(m, 1)
+		NotZero(m, []int{})
+		NotZero(m, new(int))
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		NotZero(m, struct{ A int }{})
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail nil", func(t *testing.T) {
+		m := &mockT{}
+		NotZero(m, nil)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestSame(t *testing.T) {
+	a, b := 1, 1
+
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		Same(m, &a, &a)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		Same(m, &a, &b)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestNotSame(t *testing.T) {
+	a, b := 1, 1
+
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		NotSame(m, &a, &b)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		NotSame(m, &a, &a)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestPointsTo(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		value := "gopher"
+		PointsTo(m, "gopher", &value)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		value := "gopher"
+		PointsTo(m, "rust", &value)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail nil", func(t *testing.T) {
+		m := &mockT{}
+		PointsTo(m, "gopher", nil)
 		if !m.failed {
 			t.Error("expected fail")
 		}
@@ -718,6 +1066,22 @@ func TestEqualMap(t *testing.T) {
 	})
 }
 
+func TestEqualMapMessage(t *testing.T) {
+	m := &mockT{}
+	EqualMap(m, map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}, map[string]int{"b": 20, "c": 3, "e": 5, "d": 40})
+	want := "expected map[a:1 b:2 c:3 d:4], got map[b:20 c:3 d:40 e:5]\n" +
+		"missing keys:\n" +
+		"  [\"a\"]: 1\n" +
+		"extra keys:\n" +
+		"  [\"e\"]: 5\n" +
+		"changed values:\n" +
+		"  [\"b\"]: expected 2, got 20\n" +
+		"  [\"d\"]: expected 4, got 40"
+	if m.message != want {
+		t.Errorf("got:\n%s\nwant:\n%s", m.message, want)
+	}
+}
+
 func TestNotEqualMap(t *testing.T) {
 	t.Run("pass", func(t *testing.T) {
 		m := &mockT{}
@@ -736,6 +1100,32 @@ func TestNotEqualMap(t *testing.T) {
 	})
 }
 
+func TestEqualMapFunc(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		EqualMapFunc(m, map[string][]int{"a": {1}}, map[string][]int{"a": {1}}, slices.Equal)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		EqualMapFunc(m, map[string][]int{"a": {1}}, map[string][]int{"a": {2}}, slices.Equal)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail missing key", func(t *testing.T) {
+		m := &mockT{}
+		EqualMapFunc(m, map[string][]int{"a": {1}}, map[string][]int{"b": {1}}, slices.Equal)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
 func TestContainsMapKey(t *testing.T) {
 	t.Run("pass", func(t *testing.T) {
 		m := &mockT{}
@@ -771,3 +1161,68 @@ func TestNotContainsMapKey(t *testing.T) {
 		}
 	})
 }
+
+func TestContainsMapEntry(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		ContainsMapEntry(m, map[string]int{"a": 1}, "a", 1)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail missing key", func(t *testing.T) {
+		m := &mockT{}
+		ContainsMapEntry(m, map[string]int{"a": 1}, "b", 1)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail different value", func(t *testing.T) {
+		m := &mockT{}
+		ContainsMapEntry(m, map[string]int{"a": 1}, "a", 2)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestContainsMapValue(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		ContainsMapValue(m, map[string]int{"a": 1, "b": 2}, 2)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		ContainsMapValue(m, map[string]int{"a": 1}, 2)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestNotContainsMapValue(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		NotContainsMapValue(m, map[string]int{"a": 1}, 2)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		NotContainsMapValue(m, map[string]int{"b": 2, "a": 2}, 2)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+		if want := "expected map map[a:2 b:2] not to contain value 2, found at keys [a b]"; m.message != want {
+			t.Errorf("got %q, want %q", m.message, want)
+		}
+	})
+}
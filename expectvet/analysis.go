@@ -0,0 +1,115 @@
+// Package expectvet reports common misuse of the expect assertion functions.
+//
+// The analyzer depends only on the standard go/ast and go/types packages.
+// [Pass], [Diagnostic], [SuggestedFix] and [TextEdit] mirror the types of the
+// same names in golang.org/x/tools/go/analysis, so wrapping [Run] in an
+// analysis.Analyzer for a multichecker only requires copying fields:
+//
+//	var Analyzer = &analysis.Analyzer{
+//		Name: expectvet.Name,
+//		Doc:  expectvet.Doc,
+//		Run: func(pass *analysis.Pass) (any, error) {
+//			return nil, expectvet.Run(&expectvet.Pass{
+//				Fset:      pass.Fset,
+//				Files:     pass.Files,
+//				Pkg:       pass.Pkg,
+//				TypesInfo: pass.TypesInfo,
+//				Report:    func(d expectvet.Diagnostic) { pass.Report(convert(d)) },
+//			})
+//		},
+//	}
+//
+// The expect-vet command runs the analyzer standalone or through
+// go vet -vettool.
+package expectvet
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+)
+
+// A Pass provides information to the Run function that applies an analyzer
+// to a single package.
+type Pass struct {
+	Fset      *token.FileSet
+	Files     []*ast.File
+	Pkg       *types.Package
+	TypesInfo *types.Info
+	// Report reports a diagnostic.
+	Report func(Diagnostic)
+}
+
+// A Diagnostic is a message associated with a source location or range.
+type Diagnostic struct {
+	Pos            token.Pos
+	End            token.Pos
+	Category       string
+	Message        string
+	SuggestedFixes []SuggestedFix
+}
+
+// A SuggestedFix is a code change that resolves a diagnostic.
+type SuggestedFix struct {
+	Message   string
+	TextEdits []TextEdit
+}
+
+// A TextEdit replaces the source in [Pos, End) with NewText.
+type TextEdit struct {
+	Pos     token.Pos
+	End     token.Pos
+	NewText []byte
+}
+
+// NewTypesInfo returns a types.Info with all the maps the analyzer needs.
+func NewTypesInfo() *types.Info {
+	return &types.Info{
+		Types:      map[ast.Expr]types.TypeAndValue{},
+		Defs:       map[*ast.Ident]types.Object{},
+		Uses:       map[*ast.Ident]types.Object{},
+		Implicits:  map[ast.Node]types.Object{},
+		Selections: map[*ast.SelectorExpr]*types.Selection{},
+		Instances:  map[*ast.Ident]types.Instance{},
+	}
+}
+
+// ApplyEdits applies edits to src, the contents of the file containing them.
+// Identical edits are applied once; other overlapping edits are an error.
+func ApplyEdits(fset *token.FileSet, src []byte, edits []TextEdit) ([]byte, error) {
+	type span struct {
+		start, end int
+		text       []byte
+	}
+	spans := make([]span, 0, len(edits))
+	for _, e := range edits {
+		start, end := fset.Position(e.Pos).Offset, fset.Position(e.End).Offset
+		if start < 0 || end < start || end > len(src) {
+			return nil, fmt.Errorf("edit %d:%d out of range", start, end)
+		}
+		spans = append(spans, span{start, end, e.NewText})
+	}
+	slices.SortStableFunc(spans, func(a, b span) int {
+		return cmp.Or(cmp.Compare(a.start, b.start), cmp.Compare(a.end, b.end))
+	})
+
+	var out bytes.Buffer
+	last := 0
+	for i, s := range spans {
+		if i > 0 && s.start == spans[i-1].start && s.end == spans[i-1].end && bytes.Equal(s.text, spans[i-1].text) {
+			continue
+		}
+		if s.start < last {
+			return nil, fmt.Errorf("overlapping edits at offset %d", s.start)
+		}
+		out.Write(src[last:s.start])
+		out.Write(s.text)
+		last = s.end
+	}
+	out.Write(src[last:])
+	return out.Bytes(), nil
+}
@@ -0,0 +1,342 @@
+package expectvet
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/printer"
+	"go/token"
+	"go/types"
+)
+
+// Name is the name of the analyzer.
+const Name = "expect"
+
+// Doc documents the analyzer.
+const Doc = `check for common mistakes in expect assertions
+
+The expect analyzer reports:
+  - literals passed as the actual value, which usually means the expected
+    and actual arguments of Equal and friends are swapped;
+  - Equal or NotEqual against true or false instead of True or False;
+  - ErrorAs targets that are not pointers to an error or interface type;
+  - Nil or NotNil on errors instead of NoError or Error;
+  - Len on values whose type has no length.`
+
+const expectPath = "github.com/lumertzg/expect"
+
+var errorType = types.Universe.Lookup("error").Type()
+
+// expectedActual lists the assertions whose second and third arguments are
+// the expected and actual values.
+var expectedActual = map[string]bool{
+	"Equal":          true,
+	"NotEqual":       true,
+	"EqualFunc":      true,
+	"EqualSlice":     true,
+	"NotEqualSlice":  true,
+	"EqualSliceFunc": true,
+	"EqualMap":       true,
+	"NotEqualMap":    true,
+	"EqualMapFunc":   true,
+	"DeepEqual":      true,
+	"NotDeepEqual":   true,
+}
+
+// Run reports common misuse of expect assertions in the package of pass,
+// with suggested fixes where the intent is clear.
+func Run(pass *Pass) error {
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			name, ident := callee(pass.TypesInfo, call)
+			if ident == nil || call.Ellipsis.IsValid() {
+				return true
+			}
+			c := &checker{pass: pass, call: call, name: name, ident: ident}
+			c.check()
+			return true
+		})
+	}
+	return nil
+}
+
+// callee returns the name of the expect function called by call, and the
+// identifier naming it.
+func callee(info *types.Info, call *ast.CallExpr) (string, *ast.Ident) {
+	fun := ast.Unparen(call.Fun)
+	switch f := fun.(type) {
+	case *ast.IndexExpr:
+		fun = f.X
+	case *ast.IndexListExpr:
+		fun = f.X
+	}
+
+	var ident *ast.Ident
+	switch f := fun.(type) {
+	case *ast.Ident:
+		ident = f
+	case *ast.SelectorExpr:
+		ident = f.Sel
+	default:
+		return "", nil
+	}
+
+	fn, ok := info.Uses[ident].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != expectPath {
+		return "", nil
+	}
+	if sig, ok := fn.Type().(*types.Signature); !ok || sig.Recv() != nil {
+		return "", nil
+	}
+	return fn.Name(), ident
+}
+
+type checker struct {
+	pass  *Pass
+	call  *ast.CallExpr
+	name  string
+	ident *ast.Ident
+}
+
+func (c *checker) check() {
+	args := c.call.Args
+	switch {
+	case c.name == "Equal" || c.name == "NotEqual":
+		if len(args) == 3 && (c.checkBoolCompare() || c.checkSwapped()) {
+			return
+		}
+	case expectedActual[c.name]:
+		if len(args) >= 3 {
+			c.checkSwapped()
+		}
+	case c.name == "ErrorAs":
+		if len(args) == 3 {
+			c.checkErrorAsTarget(args[2])
+		}
+	case c.name == "Nil" || c.name == "NotNil":
+		if len(args) == 2 {
+			c.checkNilError(args[1])
+		}
+	case c.name == "Len":
+		if len(args) == 3 {
+			c.checkLen(args[1])
+		}
+	}
+}
+
+// checkBoolCompare reports Equal(t, true, x) and similar calls that should
+// use True or False.
+func (c *checker) checkBoolCompare() bool {
+	expected, actual := c.call.Args[1], c.call.Args[2]
+	value, other := expected, actual
+	b, ok := c.boolConstant(value)
+	if !ok {
+		value, other = actual, expected
+		if b, ok = c.boolConstant(value); !ok {
+			return false
+		}
+	}
+	if _, isConst := c.boolConstant(other); isConst {
+		return false
+	}
+	tv, ok := c.pass.TypesInfo.Types[other]
+	if !ok || !types.AssignableTo(tv.Type, types.Typ[types.Bool]) {
+		return false
+	}
+
+	if c.name == "NotEqual" {
+		b = !b
+	}
+	replacement := "False"
+	if b {
+		replacement = "True"
+	}
+
+	c.report("bool-compare", fmt.Sprintf("use %s instead of %s with %s", replacement, c.name, c.source(value)),
+		SuggestedFix{
+			Message: "Replace with " + replacement,
+			TextEdits: []TextEdit{
+				c.renameEdit(replacement),
+				{Pos: expected.Pos(), End: actual.End(), NewText: []byte(c.source(other))},
+			},
+		})
+	return true
+}
+
+func (c *checker) boolConstant(e ast.Expr) (bool, bool) {
+	tv, ok := c.pass.TypesInfo.Types[e]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.Bool {
+		return false, false
+	}
+	return constant.BoolVal(tv.Value), true
+}
+
+// checkSwapped reports literals passed as the actual value while the expected
+// value is computed, which suggests the arguments are swapped.
+func (c *checker) checkSwapped() bool {
+	expected, actual := c.call.Args[1], c.call.Args[2]
+	if !c.isLiteral(actual) || c.isLiteral(expected) {
+		return false
+	}
+	c.report("swapped", fmt.Sprintf("%s: literal %s passed as the actual value; are the expected and actual arguments swapped?",
+		c.name, c.source(actual)),
+		SuggestedFix{
+			Message: "Swap expected and actual",
+			TextEdits: []TextEdit{
+				{Pos: expected.Pos(), End: expected.End(), NewText: []byte(c.source(actual))},
+				{Pos: actual.Pos(), End: actual.End(), NewText: []byte(c.source(expected))},
+			},
+		})
+	return true
+}
+
+// isLiteral reports whether e is a constant or a composite literal.
+func (c *checker) isLiteral(e ast.Expr) bool {
+	e = ast.Unparen(e)
+	if _, ok := e.(*ast.CompositeLit); ok {
+		return true
+	}
+	if u, ok := e.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		if _, ok := ast.Unparen(u.X).(*ast.CompositeLit); ok {
+			return true
+		}
+	}
+	tv, ok := c.pass.TypesInfo.Types[e]
+	return ok && tv.Value != nil
+}
+
+// checkErrorAsTarget reports ErrorAs targets that errors.As would reject.
+func (c *checker) checkErrorAsTarget(target ast.Expr) {
+	tv, ok := c.pass.TypesInfo.Types[target]
+	if !ok {
+		return
+	}
+	if tv.IsNil() {
+		c.report("erroras-target", "ErrorAs target must be a non-nil pointer, got nil")
+		return
+	}
+
+	if _, isParam := tv.Type.(*types.TypeParam); isParam {
+		return
+	}
+	ptr, isPtr := tv.Type.Underlying().(*types.Pointer)
+	if isPtr && isErrorTarget(ptr.Elem()) {
+		return
+	}
+
+	switch {
+	case isErrorTarget(tv.Type) && !types.Identical(tv.Type.Underlying(), types.NewInterfaceType(nil, nil)):
+		msg := fmt.Sprintf("ErrorAs target must be a pointer to %s, got %s", c.typeString(tv.Type), c.typeString(tv.Type))
+		if !tv.Addressable() {
+			c.report("erroras-target", msg)
+			return
+		}
+		c.report("erroras-target", msg, SuggestedFix{
+			Message:   "Take the address of the target",
+			TextEdits: []TextEdit{{Pos: target.Pos(), End: target.Pos(), NewText: []byte("&")}},
+		})
+	case isPtr:
+		c.report("erroras-target", fmt.Sprintf("ErrorAs target must point to an error or interface type, got %s",
+			c.typeString(tv.Type)))
+	case !types.IsInterface(tv.Type):
+		c.report("erroras-target", fmt.Sprintf("ErrorAs target must be a pointer, got %s", c.typeString(tv.Type)))
+	}
+}
+
+// isErrorTarget reports whether errors.As can store into a *t.
+func isErrorTarget(t types.Type) bool {
+	return types.IsInterface(t) || types.Implements(t, errorType.Underlying().(*types.Interface))
+}
+
+// checkNilError reports Nil and NotNil called on errors.
+func (c *checker) checkNilError(value ast.Expr) {
+	tv, ok := c.pass.TypesInfo.Types[value]
+	if !ok || !types.Identical(tv.Type, errorType) {
+		return
+	}
+	replacement := "NoError"
+	if c.name == "NotNil" {
+		replacement = "Error"
+	}
+	c.report("nil-error", fmt.Sprintf("use %s instead of %s to check errors", replacement, c.name),
+		SuggestedFix{
+			Message:   "Replace with " + replacement,
+			TextEdits: []TextEdit{c.renameEdit(replacement)},
+		})
+}
+
+// checkLen reports Len on values whose type has no length.
+func (c *checker) checkLen(value ast.Expr) {
+	tv, ok := c.pass.TypesInfo.Types[value]
+	if !ok || tv.Type == nil {
+		return
+	}
+	if _, isParam := tv.Type.(*types.TypeParam); isParam || types.IsInterface(tv.Type) {
+		return
+	}
+
+	switch u := tv.Type.Underlying().(type) {
+	case *types.Array, *types.Slice, *types.Map, *types.Chan:
+		return
+	case *types.Basic:
+		if u.Info()&types.IsString != 0 {
+			return
+		}
+	case *types.Pointer:
+		if _, ok := u.Elem().Underlying().(*types.Array); ok {
+			deref := "*" + c.operand(value)
+			if addr, ok := ast.Unparen(value).(*ast.UnaryExpr); ok && addr.Op == token.AND {
+				deref = c.source(addr.X)
+			}
+			c.report("len-type", fmt.Sprintf("Len does not support %s; dereference the pointer", c.typeString(tv.Type)),
+				SuggestedFix{
+					Message:   "Dereference the pointer",
+					TextEdits: []TextEdit{{Pos: value.Pos(), End: value.End(), NewText: []byte(deref)}},
+				})
+			return
+		}
+	}
+	c.report("len-type", fmt.Sprintf("Len does not support %s, which has no length", c.typeString(tv.Type)))
+}
+
+func (c *checker) report(category, message string, fixes ...SuggestedFix) {
+	c.pass.Report(Diagnostic{
+		Pos:            c.call.Pos(),
+		End:            c.call.End(),
+		Category:       category,
+		Message:        message,
+		SuggestedFixes: fixes,
+	})
+}
+
+// renameEdit replaces the called function, including any explicit type
+// arguments, with the expect function name.
+func (c *checker) renameEdit(name string) TextEdit {
+	return TextEdit{Pos: c.ident.Pos(), End: ast.Unparen(c.call.Fun).End(), NewText: []byte(name)}
+}
+
+func (c *checker) source(e ast.Expr) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, c.pass.Fset, e); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// operand renders e so it can be used as the operand of a unary operator.
+func (c *checker) operand(e ast.Expr) string {
+	switch ast.Unparen(e).(type) {
+	case *ast.Ident, *ast.SelectorExpr, *ast.IndexExpr, *ast.CallExpr, *ast.ParenExpr:
+		return c.source(e)
+	}
+	return "(" + c.source(e) + ")"
+}
+
+func (c *checker) typeString(t types.Type) string {
+	return types.TypeString(t, types.RelativeTo(c.pass.Pkg))
+}
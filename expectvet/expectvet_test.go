@@ -0,0 +1,121 @@
+package expectvet
+
+import (
+	"flag"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+var wantRE = regexp.MustCompile("// want `([^`]*)`")
+
+func TestRun(t *testing.T) {
+	const filename = "testdata/a.go"
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := NewTypesInfo()
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("a", fset, []*ast.File{file}, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var diags []Diagnostic
+	err = Run(&Pass{
+		Fset:      fset,
+		Files:     []*ast.File{file},
+		Pkg:       pkg,
+		TypesInfo: info,
+		Report:    func(d Diagnostic) { diags = append(diags, d) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[int]*regexp.Regexp{}
+	for i, line := range strings.Split(string(src), "\n") {
+		if m := wantRE.FindStringSubmatch(line); m != nil {
+			want[i+1] = regexp.MustCompile(m[1])
+		}
+	}
+
+	var edits []TextEdit
+	for _, d := range diags {
+		line := fset.Position(d.Pos).Line
+		re, ok := want[line]
+		if !ok {
+			t.Errorf("line %d: unexpected diagnostic %q", line, d.Message)
+			continue
+		}
+		if !re.MatchString(d.Message) {
+			t.Errorf("line %d: diagnostic %q does not match %q", line, d.Message, re)
+		}
+		delete(want, line)
+		for _, fix := range d.SuggestedFixes {
+			edits = append(edits, fix.TextEdits...)
+		}
+	}
+	for line, re := range want {
+		t.Errorf("line %d: missing diagnostic matching %q", line, re)
+	}
+
+	got, err := ApplyEdits(fset, src, edits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *update {
+		if err := os.WriteFile(filename+".golden", got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	golden, err := os.ReadFile(filename + ".golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(golden) {
+		t.Errorf("fixed source does not match golden file:\n%s", got)
+	}
+}
+
+func TestApplyEditsOverlap(t *testing.T) {
+	fset := token.NewFileSet()
+	src := []byte("abcdef")
+	f := fset.AddFile("x.go", -1, len(src))
+	pos := func(off int) token.Pos { return f.Pos(off) }
+
+	_, err := ApplyEdits(fset, src, []TextEdit{
+		{Pos: pos(0), End: pos(3), NewText: []byte("x")},
+		{Pos: pos(2), End: pos(4), NewText: []byte("y")},
+	})
+	if err == nil {
+		t.Error("expected error for overlapping edits")
+	}
+
+	got, err := ApplyEdits(fset, src, []TextEdit{
+		{Pos: pos(4), End: pos(6), NewText: []byte("Z")},
+		{Pos: pos(0), End: pos(1), NewText: []byte("A")},
+		{Pos: pos(0), End: pos(1), NewText: []byte("A")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "AbcdZ" {
+		t.Errorf("expected AbcdZ, got %s", got)
+	}
+}
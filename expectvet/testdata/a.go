@@ -0,0 +1,43 @@
+package a
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lumertzg/expect"
+)
+
+type myErr struct{}
+
+func (*myErr) Error() string { return "my" }
+
+func compute() int { return 42 }
+
+func TestA(t *testing.T) {
+	got := compute()
+	var err error
+	var ok bool
+	var target *myErr
+	var arr [3]int
+	values := []int{1, 2}
+
+	expect.Equal(t, 42, got)
+	expect.Equal(t, got, 42)                  // want `literal 42 passed as the actual value`
+	expect.EqualSlice(t, values, []int{1, 2}) // want `literal \[\]int\{1, 2\} passed as the actual value`
+	expect.DeepEqual(t, values, []int{1, 2})  // want `DeepEqual: literal \[\]int\{1, 2\} passed as the actual value`
+	expect.Equal(t, true, ok)                 // want `use True instead of Equal with true`
+	expect.Equal(t, ok, false)                // want `use False instead of Equal with false`
+	expect.NotEqual[bool](t, true, ok)        // want `use False instead of NotEqual with true`
+	expect.ErrorAs(t, err, target)            // want `ErrorAs target must be a pointer to \*myErr, got \*myErr`
+	expect.ErrorAs(t, err, &target)
+	expect.ErrorAs(t, err, &got)      // want `ErrorAs target must point to an error or interface type, got \*int`
+	expect.ErrorAs(t, err, nil)       // want `ErrorAs target must be a non-nil pointer, got nil`
+	expect.Nil(t, err)                // want `use NoError instead of Nil to check errors`
+	expect.NotNil(t, errors.New("x")) // want `use Error instead of NotNil to check errors`
+	expect.Nil(t, target)
+	expect.Len(t, values, 2)
+	expect.Len(t, "ab", 2)
+	expect.Len(t, &arr, 3) // want `Len does not support \*\[3\]int; dereference the pointer`
+	expect.Len(t, got, 1)  // want `Len does not support int, which has no length`
+	expect.Len(t, any(values), 2)
+}
@@ -0,0 +1,359 @@
+// Package rewrite provides the plumbing shared by the source rewriting
+// commands: loading and type-checking files, applying text edits that keep
+// the surrounding formatting and comments, and managing imports.
+package rewrite
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lumertzg/expect/internal/diff"
+)
+
+// ExpectPath is the import path of the expect package.
+const ExpectPath = "github.com/lumertzg/expect"
+
+// A File is a parsed Go source file.
+type File struct {
+	Name string
+	Src  []byte
+	AST  *ast.File
+	Fset *token.FileSet
+	// Info holds type information for the file's package. Type checking is
+	// best effort, so expressions may be missing from it.
+	Info *types.Info
+
+	edits []edit
+}
+
+type edit struct {
+	start, end int
+	text       string
+}
+
+// Load parses the Go files named by paths. A path is a file, a directory, or
+// a directory followed by "/..." to include its subdirectories. Files are
+// type-checked per package when possible.
+func Load(paths []string) ([]*File, error) {
+	names, err := expand(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	type pkgKey struct{ dir, name string }
+	pkgs := map[pkgKey][]*File{}
+	var keys []pkgKey
+	var files []*File
+	for _, name := range names {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(fset, name, src, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		file := &File{Name: name, Src: src, AST: f, Fset: fset}
+		files = append(files, file)
+		k := pkgKey{filepath.Dir(name), f.Name.Name}
+		if _, ok := pkgs[k]; !ok {
+			keys = append(keys, k)
+		}
+		pkgs[k] = append(pkgs[k], file)
+	}
+
+	imp := importer.ForCompiler(fset, "source", nil)
+	for _, k := range keys {
+		typeCheck(fset, imp, pkgs[k])
+	}
+	return files, nil
+}
+
+// typeCheck records whatever type information can be computed for files,
+// ignoring errors.
+func typeCheck(fset *token.FileSet, imp types.Importer, files []*File) {
+	info := &types.Info{
+		Types: map[ast.Expr]types.TypeAndValue{},
+		Defs:  map[*ast.Ident]types.Object{},
+		Uses:  map[*ast.Ident]types.Object{},
+	}
+	asts := make([]*ast.File, len(files))
+	for i, f := range files {
+		asts[i] = f.AST
+		f.Info = info
+	}
+	conf := types.Config{
+		Importer: imp,
+		Error:    func(error) {},
+	}
+	conf.Check(files[0].AST.Name.Name, fset, asts, info) // errors are expected
+}
+
+func expand(paths []string) ([]string, error) {
+	var names []string
+	for _, path := range paths {
+		dir, recursive := strings.CutSuffix(path, "/...")
+		if dir == "" {
+			dir = "."
+		}
+		info, err := os.Stat(dir)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			names = append(names, path)
+			continue
+		}
+		err = filepath.WalkDir(dir, func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				base := d.Name()
+				if name != dir && (!recursive || base == "testdata" || base == "vendor" || strings.HasPrefix(base, ".") || strings.HasPrefix(base, "_")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(name, ".go") {
+				names = append(names, name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names), nil
+}
+
+// TypeOf returns the type of e, or nil if it is unknown.
+func (f *File) TypeOf(e ast.Expr) types.Type {
+	if f.Info == nil {
+		return nil
+	}
+	tv, ok := f.Info.Types[e]
+	if !ok || tv.Type == nil || tv.Type == types.Typ[types.Invalid] {
+		return nil
+	}
+	return tv.Type
+}
+
+// Source returns the source text of node.
+func (f *File) Source(node ast.Node) string {
+	return string(f.Src[f.Offset(node.Pos()):f.Offset(node.End())])
+}
+
+// Offset returns the byte offset of pos in the file.
+func (f *File) Offset(pos token.Pos) int {
+	return f.Fset.Position(pos).Offset
+}
+
+// Position returns the position of pos, for diagnostics.
+func (f *File) Position(pos token.Pos) token.Position {
+	return f.Fset.Position(pos)
+}
+
+// Replace replaces the source of node with text.
+func (f *File) Replace(node ast.Node, text string) {
+	f.ReplaceRange(node.Pos(), node.End(), text)
+}
+
+// ReplaceRange replaces the source in [pos, end) with text.
+func (f *File) ReplaceRange(pos, end token.Pos, text string) {
+	f.edits = append(f.edits, edit{f.Offset(pos), f.Offset(end), text})
+}
+
+// Changed reports whether any edits were recorded.
+func (f *File) Changed() bool {
+	return len(f.edits) > 0
+}
+
+// ImportName returns the name under which the file imports path, or "" if it
+// does not. Dot and blank imports are reported as "." and "_".
+func (f *File) ImportName(path string) string {
+	for _, spec := range f.AST.Imports {
+		if p, _ := strconv.Unquote(spec.Path.Value); p == path {
+			if spec.Name != nil {
+				return spec.Name.Name
+			}
+			return pathName(path)
+		}
+	}
+	return ""
+}
+
+// pathName guesses the package name from its import path.
+func pathName(path string) string {
+	name := path[strings.LastIndex(path, "/")+1:]
+	if strings.HasPrefix(name, "v") && name != "v" {
+		if _, err := strconv.Atoi(name[1:]); err == nil {
+			prev := strings.TrimSuffix(path, "/"+name)
+			name = prev[strings.LastIndex(prev, "/")+1:]
+		}
+	}
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// AddImport adds an import of path unless the file already has one, and
+// returns the name to refer to the package by.
+func (f *File) AddImport(path string) string {
+	if name := f.ImportName(path); name != "" && name != "_" {
+		return name
+	}
+	spec := strconv.Quote(path)
+	if len(f.AST.Imports) == 0 {
+		f.ReplaceRange(f.AST.Name.End(), f.AST.Name.End(), "\n\nimport "+spec)
+		return pathName(path)
+	}
+	var last *ast.GenDecl
+	for _, decl := range f.AST.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			last = gen
+		}
+	}
+	if last.Lparen.IsValid() {
+		// Start a new group when adding a third-party package after the
+		// standard library, as goimports does.
+		prev, _ := strconv.Unquote(last.Specs[len(last.Specs)-1].(*ast.ImportSpec).Path.Value)
+		if isStd(prev) && !isStd(path) {
+			spec = "\n" + spec
+		}
+		f.ReplaceRange(last.Rparen, last.Rparen, spec+"\n")
+	} else {
+		f.ReplaceRange(last.End(), last.End(), "\nimport "+spec)
+	}
+	return pathName(path)
+}
+
+// ReplaceImport replaces the import of old with an unnamed import of new,
+// keeping its place among the other imports. It reports whether the file
+// imported old.
+func (f *File) ReplaceImport(old, new string) bool {
+	for _, spec := range f.AST.Imports {
+		if p, _ := strconv.Unquote(spec.Path.Value); p != old {
+			continue
+		}
+		f.ReplaceRange(spec.Pos(), spec.Path.End(), strconv.Quote(new))
+		return true
+	}
+	return false
+}
+
+// isStd reports whether path looks like a standard library import path.
+func isStd(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+// RemoveImport removes the import of path if the file has one.
+func (f *File) RemoveImport(path string) {
+	for _, decl := range f.AST.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		for _, s := range gen.Specs {
+			spec := s.(*ast.ImportSpec)
+			if p, _ := strconv.Unquote(spec.Path.Value); p != path {
+				continue
+			}
+			if len(gen.Specs) == 1 && !gen.Lparen.IsValid() {
+				f.Replace(gen, "")
+				return
+			}
+			start := spec.Pos()
+			if spec.Doc != nil {
+				start = spec.Doc.Pos()
+			}
+			end := spec.End()
+			if spec.Comment != nil {
+				end = spec.Comment.End()
+			}
+			f.ReplaceRange(start, end, "")
+			return
+		}
+	}
+}
+
+// UsesName reports whether the file refers to name as a package qualifier
+// outside the given nodes.
+func (f *File) UsesName(name string, except map[ast.Node]bool) bool {
+	used := false
+	ast.Inspect(f.AST, func(n ast.Node) bool {
+		if used || except[n] {
+			return false
+		}
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok && id.Name == name {
+				used = true
+			}
+		}
+		return true
+	})
+	return used
+}
+
+// Result applies the recorded edits and returns the formatted source.
+func (f *File) Result() ([]byte, error) {
+	edits := slices.Clone(f.edits)
+	slices.SortStableFunc(edits, func(a, b edit) int {
+		return cmp.Or(cmp.Compare(a.start, b.start), cmp.Compare(a.end, b.end))
+	})
+	var out bytes.Buffer
+	last := 0
+	for _, e := range edits {
+		if e.start < last {
+			return nil, fmt.Errorf("%s: overlapping edits at offset %d", f.Name, e.start)
+		}
+		out.Write(f.Src[last:e.start])
+		out.WriteString(e.text)
+		last = e.end
+	}
+	out.Write(f.Src[last:])
+
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%s: formatting rewritten source: %v", f.Name, err)
+	}
+	return formatted, nil
+}
+
+// Output writes the rewritten file if write is set and prints its diff to
+// stdout if showDiff is set. It reports whether the file changed.
+func (f *File) Output(write, showDiff bool) (bool, error) {
+	if !f.Changed() {
+		return false, nil
+	}
+	out, err := f.Result()
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(out, f.Src) {
+		return false, nil
+	}
+	if showDiff {
+		os.Stdout.Write(diff.Unified(f.Name+".orig", f.Name, f.Src, out))
+	}
+	if write {
+		if err := os.WriteFile(f.Name, out, 0o666); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
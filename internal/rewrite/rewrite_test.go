@@ -0,0 +1,75 @@
+package rewrite
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func load(t *testing.T, src string) *File {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "a.go")
+	if err := os.WriteFile(name, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	files, err := Load([]string{name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files[0]
+}
+
+func result(t *testing.T, f *File) string {
+	t.Helper()
+	out, err := f.Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestAddImport(t *testing.T) {
+	f := load(t, "package a\n\nimport \"fmt\"\n\nvar _ = fmt.Sprint\n")
+	if name := f.AddImport("github.com/x/y/v2"); name != "y" {
+		t.Errorf("expected name y, got %q", name)
+	}
+	want := "package a\n\nimport \"fmt\"\nimport \"github.com/x/y/v2\"\n\nvar _ = fmt.Sprint\n"
+	if got := result(t, f); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	f = load(t, "package a\n")
+	f.AddImport("fmt")
+	if got, want := result(t, f), "package a\n\nimport \"fmt\"\n"; got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestReplaceAndRemoveImport(t *testing.T) {
+	src := "package a\n\nimport (\n\t\"fmt\"\n\tx \"strings\"\n\t\"errors\" // comment\n)\n"
+	f := load(t, src)
+	if !f.ReplaceImport("strings", "bytes") {
+		t.Fatal("expected strings to be imported")
+	}
+	f.RemoveImport("errors")
+	want := "package a\n\nimport (\n\t\"bytes\"\n\t\"fmt\"\n)\n"
+	if got := result(t, f); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if name := f.ImportName("strings"); name != "x" {
+		t.Errorf("expected import name x, got %q", name)
+	}
+}
+
+func TestTypeOf(t *testing.T) {
+	f := load(t, "package a\n\nvar s = []int{1}\nvar n = len(s)\n")
+	var found bool
+	for e, tv := range f.Info.Types {
+		if f.Source(e) == "len(s)" {
+			found = tv.Type.String() == "int"
+		}
+	}
+	if !found {
+		t.Error("expected len(s) to have type int")
+	}
+}
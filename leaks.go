@@ -0,0 +1,162 @@
+package expect
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// leakWait bounds how long a leak check waits for goroutines started
+// during a test to exit. It is a variable so that tests can shorten it.
+var leakWait = 2 * time.Second
+
+type cleanupT interface {
+	Cleanup(func())
+}
+
+// NoGoroutineLeaks records the running goroutines and checks that the
+// goroutines started afterwards have exited when the test ends. If t has a
+// Cleanup method, as *testing.T does, the check runs at cleanup; it can
+// also be run explicitly by calling the returned function, once.
+//
+// Goroutines are given time to shut down gracefully before they are
+// reported with their stacks. Goroutines whose top function is one of
+// ignore, such as "net/http.(*persistConn).readLoop", are not reported.
+// Tests that run in parallel with other tests may report goroutines those
+// tests started.
+//
+//	func TestServer(t *testing.T) {
+//		expect.NoGoroutineLeaks(t)
+//		...
+//	}
+func NoGoroutineLeaks(t T, ignore ...string) func() {
+	t.Helper()
+	before := goroutineIDs()
+	var once sync.Once
+	check := func() {
+		t.Helper()
+		once.Do(func() {
+			t.Helper()
+			if leaked := waitForLeaks(before, ignore, leakWait); len(leaked) > 0 {
+				errorf(t, "NoGoroutineLeaks", "expected no leaked goroutines, found %d:\n\n%s", len(leaked), formatGoroutines(leaked))
+			}
+		})
+	}
+	if ct, ok := t.(cleanupT); ok {
+		ct.Cleanup(check)
+	}
+	return check
+}
+
+// NoGoroutineLeaksMain runs the tests of m and checks that the goroutines
+// they started have exited, as [NoGoroutineLeaks] does for a single test.
+// It returns the exit code for os.Exit, which is 1 when goroutines leaked.
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(expect.NoGoroutineLeaksMain(m))
+//	}
+func NoGoroutineLeaksMain(m interface{ Run() int }, ignore ...string) int {
+	return noGoroutineLeaksMain(m, os.Stderr, ignore)
+}
+
+func noGoroutineLeaksMain(m interface{ Run() int }, w io.Writer, ignore []string) int {
+	before := goroutineIDs()
+	code := m.Run()
+	if code != 0 {
+		return code
+	}
+	if leaked := waitForLeaks(before, ignore, leakWait); len(leaked) > 0 {
+		fmt.Fprintf(w, "expect: found %d leaked goroutines after running tests:\n\n%s\n", len(leaked), formatGoroutines(leaked))
+		return 1
+	}
+	return 0
+}
+
+// goroutine is a goroutine parsed from a stack dump.
+type goroutine struct {
+	id    uint64
+	top   string
+	stack string
+}
+
+// goroutines returns the running goroutines, the calling one first.
+func goroutines() []goroutine {
+	var gs []goroutine
+	for block := range strings.SplitSeq(goroutineStacks(), "\n\n") {
+		if g, ok := parseGoroutine(block); ok {
+			gs = append(gs, g)
+		}
+	}
+	return gs
+}
+
+// parseGoroutine parses a stack such as:
+//
+//	goroutine 7 [chan receive]:
+//	main.worker(0xc000010000)
+//		/src/main.go:12 +0x25
+func parseGoroutine(stack string) (goroutine, bool) {
+	header, rest, _ := strings.Cut(strings.TrimSpace(stack), "\n")
+	fields := strings.Fields(header)
+	if len(fields) < 2 || fields[0] != "goroutine" {
+		return goroutine{}, false
+	}
+	id, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return goroutine{}, false
+	}
+	top, _, _ := strings.Cut(rest, "\n")
+	if i := strings.LastIndex(top, "("); i > 0 {
+		top = top[:i]
+	}
+	return goroutine{id: id, top: top, stack: strings.TrimSpace(stack)}, true
+}
+
+func goroutineIDs() map[uint64]bool {
+	ids := map[uint64]bool{}
+	for _, g := range goroutines() {
+		ids[g.id] = true
+	}
+	return ids
+}
+
+// leakedGoroutines returns the goroutines other than the calling one that
+// are not in before and whose top function is not ignored.
+func leakedGoroutines(before map[uint64]bool, ignore []string) []goroutine {
+	gs := goroutines()
+	var leaked []goroutine
+	for _, g := range gs[min(1, len(gs)):] {
+		if !before[g.id] && !slices.Contains(ignore, g.top) {
+			leaked = append(leaked, g)
+		}
+	}
+	return leaked
+}
+
+// waitForLeaks polls for leaked goroutines with exponential backoff until
+// there are none or wait has passed.
+func waitForLeaks(before map[uint64]bool, ignore []string, wait time.Duration) []goroutine {
+	deadline := time.Now().Add(wait)
+	backoff := time.Millisecond
+	for {
+		leaked := leakedGoroutines(before, ignore)
+		if len(leaked) == 0 || time.Now().After(deadline) {
+			return leaked
+		}
+		time.Sleep(backoff)
+		backoff = min(2*backoff, 100*time.Millisecond)
+	}
+}
+
+func formatGoroutines(gs []goroutine) string {
+	stacks := make([]string, len(gs))
+	for i, g := range gs {
+		stacks[i] = g.stack
+	}
+	return strings.Join(stacks, "\n\n")
+}
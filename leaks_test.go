@@ -0,0 +1,134 @@
+package expect
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// cleanupMockT is a mockT that records cleanup functions.
+type cleanupMockT struct {
+	mockT
+	cleanups []func()
+}
+
+func (m *cleanupMockT) Cleanup(f func()) {
+	m.cleanups = append(m.cleanups, f)
+}
+
+func (m *cleanupMockT) runCleanups() {
+	for _, f := range m.cleanups {
+		f()
+	}
+}
+
+// blockedWorker blocks on release so that it shows up as the top function
+// of its goroutine's stack.
+func blockedWorker(release chan struct{}) {
+	<-release
+}
+
+type mainM func() int
+
+func (m mainM) Run() int { return m() }
+
+// shortLeakWait shortens the time leak checks wait for goroutines to exit
+// for the rest of the test, so that failing checks return quickly.
+func shortLeakWait(t *testing.T) {
+	wait := leakWait
+	leakWait = 50 * time.Millisecond
+	t.Cleanup(func() { leakWait = wait })
+}
+
+func TestNoGoroutineLeaks(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &cleanupMockT{}
+		NoGoroutineLeaks(m)
+		done := make(chan struct{})
+		go close(done)
+		<-done
+		m.runCleanups()
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("pass goroutine exits late", func(t *testing.T) {
+		m := &mockT{}
+		check := NoGoroutineLeaks(m)
+		go time.Sleep(20 * time.Millisecond)
+		check()
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("pass ignored", func(t *testing.T) {
+		m := &mockT{}
+		check := NoGoroutineLeaks(m, "github.com/lumertzg/expect.blockedWorker")
+		release := make(chan struct{})
+		defer close(release)
+		go blockedWorker(release)
+		check()
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		shortLeakWait(t)
+		m := &cleanupMockT{}
+		NoGoroutineLeaks(m)
+		release := make(chan struct{})
+		defer close(release)
+		go blockedWorker(release)
+		m.runCleanups()
+		if !m.failed {
+			t.Fatal("expected fail")
+		}
+		if !strings.Contains(m.message, "found 1:") || !strings.Contains(m.message, "expect.blockedWorker(") {
+			t.Errorf("expected stack of leaked goroutine, got:\n%s", m.message)
+		}
+	})
+
+	t.Run("main", func(t *testing.T) {
+		shortLeakWait(t)
+		release := make(chan struct{})
+		defer close(release)
+		var out strings.Builder
+		if code := noGoroutineLeaksMain(mainM(func() int { return 0 }), &out, nil); code != 0 {
+			t.Errorf("expected exit code 0, got %d", code)
+		}
+		if code := noGoroutineLeaksMain(mainM(func() int { return 3 }), &out, nil); code != 3 {
+			t.Errorf("expected exit code 3, got %d", code)
+		}
+		if out.Len() != 0 {
+			t.Errorf("expected no output, got:\n%s", out.String())
+		}
+
+		code := noGoroutineLeaksMain(mainM(func() int {
+			go blockedWorker(release)
+			return 0
+		}), &out, nil)
+		if code != 1 {
+			t.Errorf("expected exit code 1, got %d", code)
+		}
+		if !strings.HasPrefix(out.String(), "expect: found 1 leaked goroutines after running tests:") {
+			t.Errorf("unexpected output:\n%s", out.String())
+		}
+	})
+}
+
+func TestParseGoroutine(t *testing.T) {
+	stack := "goroutine 7 [chan receive]:\nmain.(*pool).worker(0xc000010000)\n\t/src/main.go:12 +0x25\ncreated by main.main in goroutine 1\n\t/src/main.go:20 +0x30"
+	g, ok := parseGoroutine(stack)
+	if !ok {
+		t.Fatal("expected goroutine")
+	}
+	if g.id != 7 || g.top != "main.(*pool).worker" {
+		t.Errorf("got id %d, top %q", g.id, g.top)
+	}
+	if _, ok := parseGoroutine("not a goroutine"); ok {
+		t.Error("expected no goroutine")
+	}
+}
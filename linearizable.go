@@ -0,0 +1,273 @@
+package expect
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Model is a sequential specification of an object, used by
+// [Linearizable]. S is the object's state, I the input of an operation on
+// it, such as a method and its arguments, and O the operation's output.
+type Model[S, I, O any] struct {
+	// Init returns the initial state.
+	Init func() S
+	// Step applies an operation to state and returns the resulting state
+	// and the output the operation must have. It must not modify state,
+	// which may be shared with other steps: a state holding a slice or map
+	// is updated by returning a copy.
+	Step func(state S, input I) (S, O)
+}
+
+// History records the operations called on an object from several
+// goroutines, for [Linearizable]. The zero value is an empty history.
+type History[I, O any] struct {
+	mu    sync.Mutex
+	clock uint64
+	ops   []operation[I, O]
+}
+
+// operation is a call recorded in a History. Times are logical: they order
+// the calls and returns of the history.
+type operation[I, O any] struct {
+	client    uint64
+	input     I
+	output    O
+	call, ret uint64
+	returned  bool
+}
+
+func (op operation[I, O]) String() string {
+	if !op.returned {
+		return fmt.Sprintf("%v -> (no return)", op.input)
+	}
+	return fmt.Sprintf("%v -> %v", op.input, op.output)
+}
+
+// Call records that the calling goroutine calls an operation with input,
+// and returns the function to call with its output when it returns. Calls
+// that never return may or may not have taken effect.
+//
+//	ret := history.Call(Put{Key: "a", Value: 1})
+//	ret(store.Put("a", 1))
+func (h *History[I, O]) Call(input I) func(output O) {
+	client := goroutineID()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.clock++
+	i := len(h.ops)
+	h.ops = append(h.ops, operation[I, O]{client: client, input: input, call: h.clock})
+	return func(output O) {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.clock++
+		h.ops[i].output = output
+		h.ops[i].ret = h.clock
+		h.ops[i].returned = true
+	}
+}
+
+func (h *History[I, O]) operations() []operation[I, O] {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Clone(h.ops)
+}
+
+// Linearizable asserts that the operations recorded in history are
+// linearizable with respect to model: that each appears to take effect
+// atomically at some point between its call and its return, in an order
+// in which model gives every operation the output it returned. On failure,
+// it reports a minimal sub-history that is not linearizable, drawn as a
+// timeline.
+//
+// The check is exhaustive, and may take exponential time in the number of
+// operations that overlap; histories of a few hundred operations with
+// moderate concurrency check quickly.
+func Linearizable[S, I any, O comparable](t T, model Model[S, I, O], history *History[I, O]) bool {
+	t.Helper()
+	ops := history.operations()
+	if linearizable(model, ops) {
+		return true
+	}
+	sub := minimalHistory(model, ops)
+	errorf(t, "Linearizable", "expected history of %d operations to be linearizable, "+
+		"but this sub-history of %d operations is not:\n%s", len(ops), len(sub), formatTimeline(sub))
+	return false
+}
+
+// linearizable searches for a linearization of ops, as in Wing and Gong's
+// algorithm: it repeatedly picks an operation that was called before every
+// remaining operation returned, and backtracks when model disagrees with
+// its output. Failed searches are memoized by the set of operations done
+// and the state reached.
+func linearizable[S, I any, O comparable](model Model[S, I, O], ops []operation[I, O]) bool {
+	done := make([]uint64, (len(ops)+63)/64)
+	failed := map[string][]S{}
+	remaining := 0
+	for _, op := range ops {
+		if op.returned {
+			remaining++
+		}
+	}
+
+	var search func(state S, remaining int) bool
+	search = func(state S, remaining int) bool {
+		if remaining == 0 {
+			return true
+		}
+		key := fmt.Sprint(done)
+		if slices.ContainsFunc(failed[key], func(s S) bool { return reflect.DeepEqual(s, state) }) {
+			return false
+		}
+
+		firstReturn := ^uint64(0)
+		for i, op := range ops {
+			if done[i/64]&(1<<(i%64)) == 0 && op.returned {
+				firstReturn = min(firstReturn, op.ret)
+			}
+		}
+		for i, op := range ops {
+			if done[i/64]&(1<<(i%64)) != 0 || op.call > firstReturn {
+				continue
+			}
+			next, output := model.Step(state, op.input)
+			if op.returned && output != op.output {
+				continue
+			}
+			done[i/64] |= 1 << (i % 64)
+			left := remaining
+			if op.returned {
+				left--
+			}
+			if search(next, left) {
+				return true
+			}
+			done[i/64] &^= 1 << (i % 64)
+		}
+		failed[key] = append(failed[key], state)
+		return false
+	}
+	return search(model.Init(), remaining)
+}
+
+// minimalHistory returns a small sub-history of the non-linearizable ops
+// that is not linearizable. It first finds the culprit: the operation
+// whose return makes the history up to that point non-linearizable. It
+// then removes other operations for as long as the history stays
+// non-linearizable with the culprit and linearizable without it. If the
+// culprit's output is one the model allows at some point of the history,
+// such as a stale read, it also keeps the operations that allow it, so that
+// what remains shows why the output is wrong when it was returned.
+func minimalHistory[S, I any, O comparable](model Model[S, I, O], ops []operation[I, O]) []operation[I, O] {
+	returns := make([]uint64, 0, len(ops))
+	for _, op := range ops {
+		if op.returned {
+			returns = append(returns, op.ret)
+		}
+	}
+	slices.Sort(returns)
+	all := make([]int, len(ops))
+	for i := range all {
+		all[i] = i
+	}
+
+	// history returns the operations of keep called by time at, as they
+	// were then: those that returned later had not returned. Unless timed,
+	// the culprit may take effect at any point.
+	culprit := -1
+	history := func(keep []int, at uint64, timed bool) []operation[I, O] {
+		var sub []operation[I, O]
+		for _, i := range keep {
+			op := ops[i]
+			switch {
+			case op.call > at:
+				continue
+			case i == culprit && !timed:
+				op.call, op.ret = 0, ^uint64(0)
+			case op.ret > at:
+				op.returned = false
+			}
+			sub = append(sub, op)
+		}
+		return sub
+	}
+	var at uint64
+	for _, ret := range returns {
+		if !linearizable(model, history(all, ret, true)) {
+			at = ret
+			break
+		}
+	}
+	culprit = slices.IndexFunc(ops, func(op operation[I, O]) bool { return op.returned && op.ret == at })
+	allowed := linearizable(model, history(all, at, false))
+
+	keep := slices.DeleteFunc(all, func(i int) bool { return ops[i].call > at })
+	for shrunk := true; shrunk; {
+		shrunk = false
+		for k := len(keep) - 1; k >= 0; k-- {
+			if keep[k] == culprit {
+				continue
+			}
+			candidate := slices.Delete(slices.Clone(keep), k, k+1)
+			context := slices.DeleteFunc(slices.Clone(candidate), func(i int) bool { return i == culprit })
+			if !linearizable(model, history(candidate, at, true)) &&
+				linearizable(model, history(context, at, true)) &&
+				(!allowed || linearizable(model, history(candidate, at, false))) {
+				keep, shrunk = candidate, true
+			}
+		}
+	}
+
+	sub := make([]operation[I, O], len(keep))
+	for k, i := range keep {
+		sub[k] = ops[i]
+	}
+	return sub
+}
+
+// formatTimeline draws ops on a common time axis, one per line, from call
+// to return:
+//
+//	goroutine 7  |-------|      Put(1) -> ok
+//	goroutine 8      |-------|  Get() -> 0
+func formatTimeline[I, O any](ops []operation[I, O]) string {
+	var times []uint64
+	for _, op := range ops {
+		times = append(times, op.call)
+		if op.returned {
+			times = append(times, op.ret)
+		}
+	}
+	slices.Sort(times)
+	const step = 4
+	column := func(time uint64) int {
+		i, _ := slices.BinarySearch(times, time)
+		return i * step
+	}
+	width := (len(times)-1)*step + 1
+
+	ops = slices.Clone(ops)
+	slices.SortFunc(ops, func(a, b operation[I, O]) int { return cmp.Compare(a.call, b.call) })
+	labels := make([]string, len(ops))
+	bars := make([]string, len(ops))
+	labelWidth, barWidth := 0, 0
+	for i, op := range ops {
+		labels[i] = fmt.Sprintf("goroutine %d", op.client)
+		start := column(op.call)
+		bar := "|" + strings.Repeat("-", width-start-1) + ">"
+		if op.returned {
+			bar = "|" + strings.Repeat("-", column(op.ret)-start-1) + "|"
+		}
+		bars[i] = strings.Repeat(" ", start) + bar
+		labelWidth, barWidth = max(labelWidth, len(labels[i])), max(barWidth, len(bars[i]))
+	}
+
+	lines := make([]string, len(ops))
+	for i, op := range ops {
+		lines[i] = fmt.Sprintf("  %-*s  %-*s  %v", labelWidth, labels[i], barWidth, bars[i], op)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,191 @@
+package expect
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// registerOp writes value to a register when write is set, and reads it
+// otherwise.
+type registerOp struct {
+	write bool
+	value int
+}
+
+func (op registerOp) String() string {
+	if op.write {
+		return fmt.Sprintf("Write(%d)", op.value)
+	}
+	return "Read()"
+}
+
+var registerModel = Model[int, registerOp, int]{
+	Init: func() int { return 0 },
+	Step: func(state int, op registerOp) (int, int) {
+		if op.write {
+			return op.value, 0
+		}
+		return state, state
+	},
+}
+
+// register is a register that is linearizable unless lost is set, in which
+// case writes only take effect on the next write.
+type register struct {
+	mu      sync.Mutex
+	value   int
+	pending int
+	lost    bool
+}
+
+func (r *register) do(op registerOp) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch {
+	case !op.write:
+		return r.value
+	case r.lost:
+		r.value, r.pending = r.pending, op.value
+	default:
+		r.value = op.value
+	}
+	return 0
+}
+
+func call(client, at, returnedAt uint64, op registerOp, output int) operation[registerOp, int] {
+	return operation[registerOp, int]{client: client, input: op, output: output, call: at, ret: returnedAt, returned: returnedAt > 0}
+}
+
+func TestLinearizable(t *testing.T) {
+	write := func(v int) registerOp { return registerOp{write: true, value: v} }
+	read := registerOp{}
+
+	t.Run("pass sequential", func(t *testing.T) {
+		m := &mockT{}
+		h := &History[registerOp, int]{}
+		r := &register{}
+		for _, op := range []registerOp{write(1), read, write(2), read} {
+			ret := h.Call(op)
+			ret(r.do(op))
+		}
+		if !Linearizable(m, registerModel, h) || m.failed {
+			t.Errorf("expected pass, got %q", m.message)
+		}
+	})
+
+	t.Run("pass overlapping", func(t *testing.T) {
+		m := &mockT{}
+		// The read overlaps the write, so it may see either value.
+		for _, output := range []int{0, 1} {
+			h := &History[registerOp, int]{ops: []operation[registerOp, int]{
+				call(1, 1, 4, write(1), 0),
+				call(2, 2, 3, read, output),
+			}}
+			if !Linearizable(m, registerModel, h) {
+				t.Errorf("expected pass reading %d, got %q", output, m.message)
+			}
+		}
+	})
+
+	t.Run("pass pending", func(t *testing.T) {
+		m := &mockT{}
+		// A write that never returned may have taken effect, or not.
+		for _, output := range []int{0, 1} {
+			h := &History[registerOp, int]{ops: []operation[registerOp, int]{
+				call(1, 1, 0, write(1), 0),
+				call(2, 2, 3, read, output),
+			}}
+			if !Linearizable(m, registerModel, h) {
+				t.Errorf("expected pass reading %d, got %q", output, m.message)
+			}
+		}
+	})
+
+	t.Run("pass concurrent", func(t *testing.T) {
+		m := &mockT{}
+		h := &History[registerOp, int]{}
+		r := &register{}
+		var wg sync.WaitGroup
+		for g := range 4 {
+			wg.Go(func() {
+				for i := range 10 {
+					op := read
+					if i%3 == 0 {
+						op = write(g + 1)
+					}
+					ret := h.Call(op)
+					ret(r.do(op))
+				}
+			})
+		}
+		wg.Wait()
+		if !Linearizable(m, registerModel, h) || m.failed {
+			t.Errorf("expected pass, got %q", m.message)
+		}
+	})
+
+	t.Run("fail stale read", func(t *testing.T) {
+		m := &mockT{}
+		h := &History[registerOp, int]{ops: []operation[registerOp, int]{
+			call(1, 1, 2, write(1), 0),
+			call(2, 3, 4, read, 1),
+			call(1, 5, 6, write(2), 0),
+			call(2, 7, 8, read, 1),
+			call(3, 9, 10, read, 2),
+		}}
+		if Linearizable(m, registerModel, h) || !m.failed {
+			t.Fatal("expected failure")
+		}
+		want := "expected history of 5 operations to be linearizable, but this sub-history of 3 operations is not:\n" +
+			"  goroutine 1  |---|                  Write(1) -> 0\n" +
+			"  goroutine 1          |---|          Write(2) -> 0\n" +
+			"  goroutine 2                  |---|  Read() -> 1"
+		if m.message != want {
+			t.Errorf("expected message:\n%s\ngot:\n%s", want, m.message)
+		}
+	})
+
+	t.Run("fail pending", func(t *testing.T) {
+		m := &mockT{}
+		h := &History[registerOp, int]{ops: []operation[registerOp, int]{
+			call(1, 1, 0, write(1), 0),
+			call(2, 2, 3, read, 1),
+			call(2, 4, 5, read, 2),
+		}}
+		Linearizable(m, registerModel, h)
+		if !strings.Contains(m.message, "sub-history of 1 operations") ||
+			!strings.HasSuffix(m.message, "Read() -> 2") {
+			t.Errorf("expected minimal sub-history, got %q", m.message)
+		}
+	})
+
+	t.Run("fail lost write", func(t *testing.T) {
+		m := &mockT{}
+		h := &History[registerOp, int]{}
+		r := &register{lost: true}
+		for _, op := range []registerOp{write(1), read, write(2), read} {
+			ret := h.Call(op)
+			ret(r.do(op))
+		}
+		if Linearizable(m, registerModel, h) {
+			t.Fatal("expected failure")
+		}
+		if !strings.Contains(m.message, "sub-history of 2 operations") {
+			t.Errorf("expected minimal sub-history, got %q", m.message)
+		}
+	})
+}
+
+func TestFormatTimeline(t *testing.T) {
+	got := formatTimeline([]operation[registerOp, int]{
+		call(8, 2, 0, registerOp{write: true, value: 2}, 0),
+		call(7, 1, 3, registerOp{}, 1),
+	})
+	want := "  goroutine 7  |-------|   Read() -> 1\n" +
+		"  goroutine 8      |---->  Write(2) -> (no return)"
+	if got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+}
@@ -0,0 +1,180 @@
+package expect
+
+import (
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Option configures how [DeepEqual], [NotDeepEqual] and [Diff] compare
+// values. Options are applied in order and can be grouped with [Options] or
+// limited to part of a value with [AtPath].
+type Option func(*options)
+
+type options struct {
+	scope string
+	rules []rule
+}
+
+// rule is a single comparison option, active for values at or below scope.
+// Only the hooks relevant to the option are set.
+type rule struct {
+	scope     string
+	ignore    func(field reflect.StructField, fieldPath string) bool
+	equal     func(x, y reflect.Value) (eq, ok bool)
+	sort      func(v reflect.Value) (reflect.Value, bool)
+	transform func(v reflect.Value) (reflect.Value, bool)
+}
+
+func (o *options) add(r rule) {
+	r.scope = o.scope
+	o.rules = append(o.rules, r)
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Options combines opts into a single option.
+func Options(opts ...Option) Option {
+	return func(o *options) {
+		for _, opt := range opts {
+			opt(o)
+		}
+	}
+}
+
+// AtPath limits opts to the value at path and the values below it. A path
+// lists struct field names separated by dots, such as "Order.Items";
+// slice indexes and map keys are not part of paths.
+//
+//	expect.DeepEqual(t, want, got, expect.AtPath("Order.Items", expect.SortSlices(cmp.Compare[string])))
+func AtPath(path string, opts ...Option) Option {
+	return func(o *options) {
+		outer := o.scope
+		o.scope = joinFields(outer, path)
+		for _, opt := range opts {
+			opt(o)
+		}
+		o.scope = outer
+	}
+}
+
+// IgnoreFields ignores struct fields with the given names. A name may be
+// qualified with the names of enclosing fields, such as "Address.Zip", to
+// ignore only the fields reached through them.
+func IgnoreFields(names ...string) Option {
+	return func(o *options) {
+		o.add(rule{ignore: func(_ reflect.StructField, fieldPath string) bool {
+			for _, name := range names {
+				if fieldPath == name || strings.HasSuffix(fieldPath, "."+name) {
+					return true
+				}
+			}
+			return false
+		}})
+	}
+}
+
+// IgnoreUnexported ignores unexported struct fields.
+func IgnoreUnexported() Option {
+	return func(o *options) {
+		o.add(rule{ignore: func(field reflect.StructField, _ string) bool {
+			return !field.IsExported()
+		}})
+	}
+}
+
+// SortSlices compares slices of E regardless of the order of their elements,
+// by sorting copies of both slices with compare before comparing them.
+//
+//	expect.DeepEqual(t, want, got, expect.SortSlices(strings.Compare))
+func SortSlices[E any](compare func(a, b E) int) Option {
+	typ := reflect.TypeFor[[]E]()
+	return func(o *options) {
+		o.add(rule{sort: func(v reflect.Value) (reflect.Value, bool) {
+			if v.Kind() != reflect.Slice || !v.CanInterface() || !v.Type().ConvertibleTo(typ) || v.Type().Elem() != typ.Elem() {
+				return v, false
+			}
+			sorted := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+			reflect.Copy(sorted, v)
+			slices.SortStableFunc(sorted.Convert(typ).Interface().([]E), compare)
+			return sorted, true
+		}})
+	}
+}
+
+// EquateEmpty treats nil and empty slices and maps as equal.
+func EquateEmpty() Option {
+	return func(o *options) {
+		o.add(rule{equal: func(x, y reflect.Value) (bool, bool) {
+			switch x.Kind() {
+			case reflect.Slice, reflect.Map:
+				if x.Len() == 0 && y.Len() == 0 {
+					return true, true
+				}
+			}
+			return false, false
+		}})
+	}
+}
+
+// EquateApprox treats floating-point numbers as equal when they differ by at
+// most margin, or by at most fraction of the smaller magnitude.
+//
+//	expect.DeepEqual(t, want, got, expect.EquateApprox(0.01, 0))
+func EquateApprox(fraction, margin float64) Option {
+	return func(o *options) {
+		o.add(rule{equal: func(x, y reflect.Value) (bool, bool) {
+			if !x.CanFloat() {
+				return false, false
+			}
+			a, b := x.Float(), y.Float()
+			if a == b {
+				return true, true
+			}
+			tolerance := max(margin, fraction*min(math.Abs(a), math.Abs(b)))
+			return math.Abs(a-b) <= tolerance, true
+		}})
+	}
+}
+
+// Transform compares values of type V by the result of fn instead. Each
+// value is transformed once, so fn may return another V.
+//
+//	expect.DeepEqual(t, want, got, expect.Transform(strings.ToLower))
+func Transform[V, R any](fn func(V) R) Option {
+	typ := reflect.TypeFor[V]()
+	return func(o *options) {
+		o.add(rule{transform: func(v reflect.Value) (reflect.Value, bool) {
+			if v.Type() != typ || !v.CanInterface() {
+				return v, false
+			}
+			r := fn(v.Interface().(V))
+			return reflect.ValueOf(&r).Elem(), true
+		}})
+	}
+}
+
+// active returns the rules that apply to the value at fieldPath.
+func (o *options) active(fieldPath string) []rule {
+	var rules []rule
+	for _, r := range o.rules {
+		if r.scope == "" || fieldPath == r.scope || strings.HasPrefix(fieldPath, r.scope+".") {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+func joinFields(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
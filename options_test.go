@@ -0,0 +1,94 @@
+package expect
+
+import (
+	"cmp"
+	"strings"
+	"testing"
+)
+
+type account struct {
+	Name      string
+	Tags      []string
+	Balance   float64
+	Address   address
+	Billing   address
+	UpdatedAt int
+	internal  int
+}
+
+type address struct {
+	City string
+	Zip  string
+}
+
+func TestOptions(t *testing.T) {
+	base := account{
+		Name:    "gopher",
+		Tags:    []string{"a", "b"},
+		Balance: 100,
+		Address: address{"Porto", "4000"},
+		Billing: address{"Porto", "4000"},
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*account)
+		opts   []Option
+		equal  bool
+	}{
+		{"IgnoreFields", func(a *account) { a.UpdatedAt = 1 }, []Option{IgnoreFields("UpdatedAt")}, true},
+		{"IgnoreFields nested", func(a *account) { a.Address.Zip = "4100" }, []Option{IgnoreFields("Zip")}, true},
+		{"IgnoreFields qualified", func(a *account) { a.Billing.Zip = "4100" }, []Option{IgnoreFields("Address.Zip")}, false},
+		{"IgnoreUnexported", func(a *account) { a.internal = 1 }, []Option{IgnoreUnexported()}, true},
+		{"no IgnoreUnexported", func(a *account) { a.internal = 1 }, nil, false},
+		{"SortSlices", func(a *account) { a.Tags = []string{"b", "a"} }, []Option{SortSlices(strings.Compare)}, true},
+		{"SortSlices other type", func(a *account) { a.Tags = []string{"b", "a"} }, []Option{SortSlices(cmp.Compare[int])}, false},
+		{"EquateEmpty non-empty", func(a *account) { a.Tags = nil }, []Option{EquateEmpty()}, false},
+		{"EquateApprox", func(a *account) { a.Balance = 100.5 }, []Option{EquateApprox(0.01, 0)}, true},
+		{"EquateApprox margin", func(a *account) { a.Balance = 100.5 }, []Option{EquateApprox(0, 0.1)}, false},
+		{"Transform", func(a *account) { a.Name = "GOPHER" }, []Option{Transform(strings.ToLower)}, true},
+		{"Transform type change", func(a *account) { a.Name = "go" }, []Option{Transform(func(s string) int { return len(s) })}, false},
+		{"AtPath", func(a *account) { a.Address.City = "PORTO" }, []Option{AtPath("Address", Transform(strings.ToUpper))}, true},
+		{"AtPath outside", func(a *account) { a.Billing.City = "PORTO" }, []Option{AtPath("Address", Transform(strings.ToUpper))}, false},
+		{"Options", func(a *account) { a.UpdatedAt, a.internal = 1, 1 }, []Option{Options(IgnoreFields("UpdatedAt"), IgnoreUnexported())}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := base
+			tt.modify(&actual)
+			diff := Diff(base, actual, tt.opts...)
+			if (diff == "") != tt.equal {
+				t.Errorf("expected equal %v, got diff:\n%s", tt.equal, diff)
+			}
+		})
+	}
+}
+
+func TestEquateEmpty(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		DeepEqual(m, map[string][]int{"a": nil}, map[string][]int{"a": {}}, EquateEmpty())
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		DeepEqual(m, map[string][]int{"a": nil}, map[string][]int{"a": {}})
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestDiff(t *testing.T) {
+	if diff := Diff([]int{1, 2}, []int{1, 2}); diff != "" {
+		t.Errorf("expected no diff, got:\n%s", diff)
+	}
+
+	want := "differences:\n  [1]: expected 2, got 3"
+	if diff := Diff([]int{1, 2}, []int{1, 3}); diff != want {
+		t.Errorf("got:\n%s\nwant:\n%s", diff, want)
+	}
+}
@@ -0,0 +1,104 @@
+package expect
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+)
+
+// IsSorted asserts that s is sorted in ascending order.
+func IsSorted[S ~[]E, E cmp.Ordered](t T, s S) {
+	t.Helper()
+	if i := unordered(s, func(a, b E) bool { return cmp.Less(b, a) }); i > 0 {
+		failUnordered(t, "IsSorted", s, i, "sorted", "less than")
+	}
+}
+
+// IsSortedFunc asserts that s is sorted in ascending order as defined by
+// compare, which returns a negative number, zero or a positive number like
+// [cmp.Compare].
+func IsSortedFunc[S ~[]E, E any](t T, s S, compare func(a, b E) int) {
+	t.Helper()
+	if i := unordered(s, func(a, b E) bool { return compare(b, a) < 0 }); i > 0 {
+		failUnordered(t, "IsSortedFunc", s, i, "sorted", "less than")
+	}
+}
+
+// IsSortedBy asserts that s is sorted in ascending order of the keys
+// returned by key.
+//
+//	expect.IsSortedBy(t, users, func(u User) string { return u.Name })
+func IsSortedBy[S ~[]E, E any, K cmp.Ordered](t T, s S, key func(E) K) {
+	t.Helper()
+	if i := unordered(s, func(a, b E) bool { return cmp.Less(key(b), key(a)) }); i > 0 {
+		failUnordered(t, "IsSortedBy", s, i, "sorted", fmt.Sprintf("less than (key %v < %v)", key(s[i]), key(s[i-1])))
+	}
+}
+
+// IsStrictlyIncreasing asserts that each element of s is greater than the
+// previous one.
+func IsStrictlyIncreasing[S ~[]E, E cmp.Ordered](t T, s S) {
+	t.Helper()
+	if i := unordered(s, func(a, b E) bool { return cmp.Compare(a, b) >= 0 }); i > 0 {
+		failUnordered(t, "IsStrictlyIncreasing", s, i, "strictly increasing", "not greater than")
+	}
+}
+
+// IsStrictlyDecreasing asserts that each element of s is less than the
+// previous one.
+func IsStrictlyDecreasing[S ~[]E, E cmp.Ordered](t T, s S) {
+	t.Helper()
+	if i := unordered(s, func(a, b E) bool { return cmp.Compare(a, b) <= 0 }); i > 0 {
+		failUnordered(t, "IsStrictlyDecreasing", s, i, "strictly decreasing", "not less than")
+	}
+}
+
+// Between asserts that low <= value <= high.
+func Between[V cmp.Ordered](t T, value, low, high V) {
+	t.Helper()
+	switch {
+	case cmp.Less(value, low):
+		errorf(t, "Between", "expected %v to be between %v and %v, but it is below the lower bound %v", value, low, high, low)
+	case cmp.Less(high, value):
+		errorf(t, "Between", "expected %v to be between %v and %v, but it is above the upper bound %v", value, low, high, high)
+	}
+}
+
+// InRange asserts that low <= value < high, the half-open range used by
+// slice expressions.
+func InRange[V cmp.Ordered](t T, value, low, high V) {
+	t.Helper()
+	switch {
+	case cmp.Less(value, low):
+		errorf(t, "InRange", "expected %v to be in range [%v, %v), but it is below the lower bound %v", value, low, high, low)
+	case !cmp.Less(value, high):
+		errorf(t, "InRange", "expected %v to be in range [%v, %v), but it is not below the upper bound %v", value, low, high, high)
+	}
+}
+
+// unordered returns the index of the first element of s that is out of
+// order with the previous one according to bad, or -1.
+func unordered[E any](s []E, bad func(prev, next E) bool) int {
+	for i := 1; i < len(s); i++ {
+		if bad(s[i-1], s[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// failUnordered reports that s[i] is out of order with s[i-1], listing the
+// elements around them.
+func failUnordered[E any](t T, assertion string, s []E, i int, order, relation string) {
+	t.Helper()
+	var b strings.Builder
+	for j := max(i-2, 0); j < min(i+2, len(s)); j++ {
+		marker := " "
+		if j == i-1 || j == i {
+			marker = ">"
+		}
+		fmt.Fprintf(&b, "\n %s [%d]: %v", marker, j, s[j])
+	}
+	errorf(t, assertion, "expected %s slice, but element %d (%v) is %s element %d (%v)%s",
+		order, i, s[i], relation, i-1, s[i-1], b.String())
+}
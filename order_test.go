@@ -0,0 +1,171 @@
+package expect
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsSorted(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		IsSorted(m, []int{1, 2, 2, 3})
+		IsSorted(m, []string{})
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		IsSorted(m, []int{1, 3, 2})
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestIsSortedFunc(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		IsSortedFunc(m, []string{"a", "B", "c"}, func(a, b string) int {
+			return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+		})
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		IsSortedFunc(m, []string{"a", "B", "c"}, strings.Compare)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestIsSortedBy(t *testing.T) {
+	type item struct {
+		name  string
+		price int
+	}
+
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		IsSortedBy(m, []item{{"b", 1}, {"a", 2}}, func(i item) int { return i.price })
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		IsSortedBy(m, []item{{"b", 1}, {"a", 2}}, func(i item) string { return i.name })
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestIsStrictlyIncreasing(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		IsStrictlyIncreasing(m, []int{1, 2, 3})
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		IsStrictlyIncreasing(m, []int{1, 2, 2})
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestIsStrictlyDecreasing(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		IsStrictlyDecreasing(m, []float64{3, 2.5, -1})
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		IsStrictlyDecreasing(m, []float64{3, 4})
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestBetween(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		Between(m, 1, 1, 10)
+		Between(m, 10, 1, 10)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail below", func(t *testing.T) {
+		m := &mockT{}
+		Between(m, 0, 1, 10)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail above", func(t *testing.T) {
+		m := &mockT{}
+		Between(m, "z", "a", "m")
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestInRange(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		InRange(m, 0, 0, 3)
+		InRange(m, 2, 0, 3)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail upper bound", func(t *testing.T) {
+		m := &mockT{}
+		InRange(m, 3, 0, 3)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail below", func(t *testing.T) {
+		m := &mockT{}
+		InRange(m, -1, 0, 3)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestUnorderedMessage(t *testing.T) {
+	m := &mockT{}
+	IsSorted(m, []int{1, 2, 5, 4, 6, 7})
+	want := "expected sorted slice, but element 3 (4) is less than element 2 (5)\n" +
+		"   [1]: 2\n" +
+		" > [2]: 5\n" +
+		" > [3]: 4\n" +
+		"   [4]: 6"
+	if m.message != want {
+		t.Errorf("got:\n%s\nwant:\n%s", m.message, want)
+	}
+}
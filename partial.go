@@ -0,0 +1,103 @@
+package expect
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// PartialEqual asserts that actual matches the template expected, comparing
+// only the fields that are set in expected. Zero fields of expected are
+// ignored, non-zero structs are matched recursively, slices are matched
+// element by element, and maps only need to contain the keys of expected.
+// Use [MatchFields] to check that a field is zero.
+//
+//	expect.PartialEqual(t, Response{Status: "ok", User: User{Name: "gopher"}}, resp)
+func PartialEqual[V any](t T, expected, actual V, opts ...Option) {
+	t.Helper()
+	c := newComparison(opts)
+	c.partial = true
+	if diffs := c.run(expected, actual); len(diffs) > 0 {
+		errorfValues(t, "PartialEqual", expected, actual, "expected %v to match %v\n%s", actual, expected, formatDifferences(diffs))
+	}
+}
+
+// MatchFields asserts that the named fields of expected and actual are
+// deeply equal, ignoring all other fields. Nested fields are named by their
+// path, such as "Address.City".
+func MatchFields[V any](t T, expected, actual V, fields ...string) {
+	t.Helper()
+	typ := reflect.TypeFor[V]()
+	for _, field := range fields {
+		if !hasFieldPath(typ, field) {
+			errorf(t, "MatchFields", "%v has no field %s", typ, field)
+			return
+		}
+	}
+
+	c := newComparison([]Option{onlyFields(fields)})
+	c.fields = fields
+	if diffs := c.run(expected, actual); len(diffs) > 0 {
+		errorfValues(t, "MatchFields", expected, actual, "expected fields %s of %v to match %v\n%s",
+			strings.Join(fields, ", "), actual, expected, formatDifferences(diffs))
+	}
+}
+
+// onlyFields ignores the struct fields that are neither one of fields, nor
+// enclosing or enclosed by one of them.
+func onlyFields(fields []string) Option {
+	return func(o *options) {
+		o.add(rule{ignore: func(_ reflect.StructField, fieldPath string) bool {
+			return !slices.ContainsFunc(fields, func(field string) bool {
+				return fieldPath == field ||
+					strings.HasPrefix(field, fieldPath+".") ||
+					strings.HasPrefix(fieldPath, field+".")
+			})
+		}})
+	}
+}
+
+// hasFieldPath reports whether the dot-separated field path can be reached
+// from typ, looking through pointers, slices, arrays and maps. Fields
+// promoted from embedded structs are named through the embedded field.
+func hasFieldPath(typ reflect.Type, fieldPath string) bool {
+	for name := range strings.SplitSeq(fieldPath, ".") {
+		typ = structType(typ)
+		if typ == nil {
+			return false
+		}
+		var next reflect.Type
+		for i := range typ.NumField() {
+			if typ.Field(i).Name == name {
+				next = typ.Field(i).Type
+			}
+		}
+		if next == nil {
+			return false
+		}
+		typ = next
+	}
+	return true
+}
+
+func structType(typ reflect.Type) reflect.Type {
+	for {
+		switch typ.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+			typ = typ.Elem()
+		case reflect.Struct:
+			return typ
+		default:
+			return nil
+		}
+	}
+}
+
+func hasExportedFields(typ reflect.Type) bool {
+	for i := range typ.NumField() {
+		if typ.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
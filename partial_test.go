@@ -0,0 +1,159 @@
+package expect
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type profile struct {
+	Name    string
+	Age     int
+	Emails  []string
+	Labels  map[string]string
+	Home    *location
+	Joined  time.Time
+	Friends []profile
+}
+
+type location struct {
+	City    string
+	Country string
+}
+
+func sampleProfile() profile {
+	return profile{
+		Name:    "gopher",
+		Age:     14,
+		Emails:  []string{"go@example.com"},
+		Labels:  map[string]string{"team": "go", "role": "mascot"},
+		Home:    &location{"Mountain View", "US"},
+		Joined:  time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC),
+		Friends: []profile{{Name: "ferris", Age: 9}},
+	}
+}
+
+func TestPartialEqual(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		PartialEqual(m, profile{Name: "gopher"}, sampleProfile())
+		PartialEqual(m, profile{Home: &location{Country: "US"}}, sampleProfile())
+		PartialEqual(m, profile{Labels: map[string]string{"team": "go"}}, sampleProfile())
+		PartialEqual(m, profile{Friends: []profile{{Name: "ferris"}}}, sampleProfile())
+		PartialEqual(m, profile{Joined: time.Date(2009, 11, 10, 1, 0, 0, 0, time.FixedZone("X", 3600))}, sampleProfile())
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("pass with options", func(t *testing.T) {
+		m := &mockT{}
+		PartialEqual(m, profile{Name: "GOPHER"}, sampleProfile(), Transform(strings.ToLower))
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		PartialEqual(m, profile{Name: "gopher", Age: 15}, sampleProfile())
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail missing key", func(t *testing.T) {
+		m := &mockT{}
+		PartialEqual(m, profile{Labels: map[string]string{"lang": "go"}}, sampleProfile())
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail slice length", func(t *testing.T) {
+		m := &mockT{}
+		PartialEqual(m, profile{Friends: []profile{{Name: "ferris"}, {Name: "duke"}}}, sampleProfile())
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestPartialEqualDifferences(t *testing.T) {
+	c := newComparison(nil)
+	c.partial = true
+	expected := profile{
+		Name:    "gopher",
+		Home:    &location{City: "Zurich"},
+		Friends: []profile{{Age: 10}},
+	}
+	want := `differences:
+  .Home.City: expected "Zurich", got "Mountain View"
+  .Friends[0].Age: expected 10, got 9`
+	if got := formatDifferences(c.run(expected, sampleProfile())); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// namedUser considers users with the same name equal.
+type namedUser struct {
+	Name string
+	Age  int
+}
+
+func (u namedUser) Equal(other namedUser) bool { return u.Name == other.Name }
+
+func TestMatchFields(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		actual := sampleProfile()
+		expected := profile{Name: "gopher", Home: &location{Country: "US"}}
+		MatchFields(m, expected, actual, "Name", "Home.Country")
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("pass zero field", func(t *testing.T) {
+		m := &mockT{}
+		actual := sampleProfile()
+		actual.Age = 0
+		MatchFields(m, profile{Name: "gopher"}, actual, "Name", "Age")
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		MatchFields(m, profile{Name: "gopher"}, sampleProfile(), "Name", "Age")
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("pass Equal method of field", func(t *testing.T) {
+		m := &mockT{}
+		expected := profile{Joined: time.Date(2009, 11, 10, 1, 0, 0, 0, time.FixedZone("X", 3600))}
+		MatchFields(m, expected, sampleProfile(), "Joined")
+		if m.failed {
+			t.Errorf("expected pass, got %q", m.message)
+		}
+	})
+
+	t.Run("fail Equal method", func(t *testing.T) {
+		m := &mockT{}
+		MatchFields(m, namedUser{"a", 1}, namedUser{"a", 2}, "Age")
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail unknown field", func(t *testing.T) {
+		m := &mockT{}
+		MatchFields(m, sampleProfile(), sampleProfile(), "Home.Street")
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
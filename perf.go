@@ -0,0 +1,138 @@
+package expect
+
+import (
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+)
+
+const (
+	// allocRuns is the number of runs allocation assertions average over.
+	allocRuns = 100
+	// durationRuns is the number of runs DurationAtMost takes the median of.
+	durationRuns = 21
+)
+
+type metricT interface {
+	ReportMetric(n float64, unit string)
+}
+
+// AllocsAtMost asserts that fn allocates at most n times per run, on
+// average over repeated runs measured with testing.AllocsPerRun. Like it,
+// AllocsAtMost sets GOMAXPROCS to 1 while measuring, and counts the
+// allocations of other goroutines too, so it should not be used in
+// parallel tests.
+//
+//	expect.AllocsAtMost(t, 0, func() { buf = strconv.AppendInt(buf[:0], 42, 10) })
+func AllocsAtMost(t T, n float64, fn func()) {
+	t.Helper()
+	if allocs := testing.AllocsPerRun(allocRuns, fn); allocs > n {
+		errorfValues(t, "AllocsAtMost", n, allocs, "expected at most %v allocations per run, got %v", n, allocs)
+	}
+}
+
+// BytesAllocatedAtMost asserts that fn allocates at most n bytes per run,
+// on average over repeated runs. It measures as [AllocsAtMost] does, from
+// the process-wide allocation statistics, so the bytes that other
+// goroutines and the race detector allocate meanwhile are counted too: n
+// should leave a margin above what fn needs, and the assertion should not
+// be used in parallel tests.
+func BytesAllocatedAtMost(t T, n uint64, fn func()) {
+	t.Helper()
+	if bytes := bytesPerRun(allocRuns, fn); bytes > n {
+		errorfValues(t, "BytesAllocatedAtMost", n, bytes, "expected at most %d bytes allocated per run, got %d", n, bytes)
+	}
+}
+
+// bytesPerRun returns the average number of bytes allocated by fn over
+// runs, after a warm-up run, as testing.AllocsPerRun does for allocations.
+func bytesPerRun(runs int, fn func()) uint64 {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+	fn()
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	for range runs {
+		fn()
+	}
+	runtime.ReadMemStats(&after)
+	return (after.TotalAlloc - before.TotalAlloc) / uint64(runs)
+}
+
+// DurationAtMost asserts that fn takes at most d to run, as the median of
+// repeated runs. The median ignores the outliers that garbage collection
+// and scheduling cause, but d should still leave room for slower machines.
+func DurationAtMost(t T, d time.Duration, fn func()) {
+	t.Helper()
+	durations := make([]time.Duration, durationRuns)
+	for i := range durations {
+		start := time.Now()
+		fn()
+		durations[i] = time.Since(start)
+	}
+	slices.Sort(durations)
+	if median := durations[len(durations)/2]; median > d {
+		errorfValues(t, "DurationAtMost", d, median, "expected median duration of at most %v over %d runs, got %v (fastest %v, slowest %v)",
+			d, len(durations), median, durations[0], durations[len(durations)-1])
+	}
+}
+
+// NsPerOpAtMost asserts that the iterations of benchmark b took at most d
+// each, on average, as reported in its ns/op result. It must be called
+// after the benchmark loop, once b.Loop has returned false.
+//
+//	func BenchmarkEncode(b *testing.B) {
+//		for b.Loop() {
+//			Encode(value)
+//		}
+//		expect.NsPerOpAtMost(b, time.Microsecond)
+//	}
+func NsPerOpAtMost(b *testing.B, d time.Duration) {
+	b.Helper()
+	nsPerOpAtMost(b, b.Elapsed(), b.N, d)
+}
+
+func nsPerOpAtMost(t T, elapsed time.Duration, n int, d time.Duration) {
+	t.Helper()
+	if n <= 0 {
+		errorf(t, "NsPerOpAtMost", "expected benchmark iterations, got none: call NsPerOpAtMost after b.Loop")
+		return
+	}
+	if perOp := elapsed / time.Duration(n); perOp > d {
+		errorfValues(t, "NsPerOpAtMost", d, perOp, "expected at most %v per operation, got %v over %d operations", d, perOp, n)
+	}
+}
+
+// MetricAtMost asserts that value, a benchmark result in unit such as
+// "B/op" or a custom "hits/op", is at most limit. If t has a ReportMetric
+// method, as *testing.B does, value is also reported with it, so the
+// checked result appears in the benchmark output.
+//
+//	for b.Loop() {
+//		cache.Get(key)
+//	}
+//	expect.MetricAtMost(b, float64(cache.Misses())/float64(b.N), "misses/op", 0.01)
+func MetricAtMost(t T, value float64, unit string, limit float64) {
+	t.Helper()
+	reportMetric(t, value, unit)
+	if value > limit {
+		errorfValues(t, "MetricAtMost", limit, value, "expected at most %v %s, got %v %s", limit, unit, value, unit)
+	}
+}
+
+// MetricAtLeast is like [MetricAtMost], for results that must be at least
+// limit, such as throughput.
+func MetricAtLeast(t T, value float64, unit string, limit float64) {
+	t.Helper()
+	reportMetric(t, value, unit)
+	if value < limit {
+		errorfValues(t, "MetricAtLeast", limit, value, "expected at least %v %s, got %v %s", limit, unit, value, unit)
+	}
+}
+
+func reportMetric(t T, value float64, unit string) {
+	if mt, ok := t.(metricT); ok {
+		mt.ReportMetric(value, unit)
+	}
+}
@@ -0,0 +1,170 @@
+package expect
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// metricMockT is a mockT that records reported metrics.
+type metricMockT struct {
+	mockT
+	metrics map[string]float64
+}
+
+func (m *metricMockT) ReportMetric(n float64, unit string) {
+	if m.metrics == nil {
+		m.metrics = map[string]float64{}
+	}
+	m.metrics[unit] = n
+}
+
+var sink []byte
+
+func TestAllocsAtMost(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		AllocsAtMost(m, 0, func() {})
+		AllocsAtMost(m, 1, func() { sink = make([]byte, 64) })
+		if m.failed {
+			t.Errorf("expected pass, got %q", m.message)
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		AllocsAtMost(m, 1, func() {
+			sink = make([]byte, 64)
+			sink = make([]byte, 64)
+		})
+		if m.message != "expected at most 1 allocations per run, got 2" {
+			t.Errorf("unexpected message: %q", m.message)
+		}
+	})
+}
+
+func TestBytesAllocatedAtMost(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		BytesAllocatedAtMost(m, 0, func() {})
+		BytesAllocatedAtMost(m, 1024, func() { sink = make([]byte, 1024) })
+		if m.failed {
+			t.Errorf("expected pass, got %q", m.message)
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		BytesAllocatedAtMost(m, 512, func() { sink = make([]byte, 1024) })
+		if m.message != "expected at most 512 bytes allocated per run, got 1024" {
+			t.Errorf("unexpected message: %q", m.message)
+		}
+	})
+}
+
+func TestDurationAtMost(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		DurationAtMost(m, time.Second, func() {})
+		if m.failed {
+			t.Errorf("expected pass, got %q", m.message)
+		}
+	})
+
+	t.Run("pass outliers", func(t *testing.T) {
+		m := &mockT{}
+		runs := 0
+		DurationAtMost(m, 5*time.Millisecond, func() {
+			if runs++; runs <= 3 {
+				time.Sleep(20 * time.Millisecond)
+			}
+		})
+		if m.failed {
+			t.Errorf("expected pass, got %q", m.message)
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		DurationAtMost(m, time.Nanosecond, func() { time.Sleep(time.Millisecond) })
+		if !strings.HasPrefix(m.message, "expected median duration of at most 1ns over 21 runs, got ") {
+			t.Errorf("unexpected message: %q", m.message)
+		}
+	})
+}
+
+func TestNsPerOpAtMost(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		nsPerOpAtMost(m, time.Second, 1000, time.Millisecond)
+		if m.failed {
+			t.Errorf("expected pass, got %q", m.message)
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		nsPerOpAtMost(m, time.Second, 100, time.Millisecond)
+		if m.message != "expected at most 1ms per operation, got 10ms over 100 operations" {
+			t.Errorf("unexpected message: %q", m.message)
+		}
+	})
+
+	t.Run("fail before loop", func(t *testing.T) {
+		m := &mockT{}
+		nsPerOpAtMost(m, 0, 0, time.Millisecond)
+		if !m.failed {
+			t.Error("expected failure")
+		}
+	})
+
+	t.Run("benchmark", func(t *testing.T) {
+		r := testing.Benchmark(func(b *testing.B) {
+			for b.Loop() {
+			}
+			NsPerOpAtMost(b, time.Second)
+		})
+		if r.N == 0 {
+			t.Error("expected benchmark to pass")
+		}
+	})
+}
+
+func TestMetricAtMost(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &metricMockT{}
+		MetricAtMost(m, 0.5, "misses/op", 1)
+		if m.failed {
+			t.Errorf("expected pass, got %q", m.message)
+		}
+		if m.metrics["misses/op"] != 0.5 {
+			t.Errorf("expected metric to be reported, got %v", m.metrics)
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &metricMockT{}
+		MetricAtMost(m, 2, "misses/op", 1)
+		if m.message != "expected at most 1 misses/op, got 2 misses/op" {
+			t.Errorf("unexpected message: %q", m.message)
+		}
+	})
+}
+
+func TestMetricAtLeast(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		MetricAtLeast(m, 2000, "ops/s", 1000)
+		if m.failed {
+			t.Errorf("expected pass, got %q", m.message)
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &metricMockT{}
+		MetricAtLeast(m, 10, "ops/s", 1000)
+		if m.message != "expected at least 1000 ops/s, got 10 ops/s" || m.metrics["ops/s"] != 10 {
+			t.Errorf("unexpected message: %q", m.message)
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package expect
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// AllSlice asserts that every element of s satisfies match.
+//
+//	expect.AllSlice(t, users, func(u User) bool { return u.Active })
+func AllSlice[S ~[]E, E any](t T, s S, match func(E) bool) {
+	t.Helper()
+	if failed := sliceIndexes(s, not(match)); len(failed) > 0 {
+		errorf(t, "AllSlice", "expected all %d elements to match, %d did not:%s",
+			len(s), len(failed), formatIndexed(s, failed))
+	}
+}
+
+// AnySlice asserts that at least one element of s satisfies match.
+func AnySlice[S ~[]E, E any](t T, s S, match func(E) bool) {
+	t.Helper()
+	if !slices.ContainsFunc(s, match) {
+		errorf(t, "AnySlice", "expected any element to match, none of %d did: %v", len(s), s)
+	}
+}
+
+// NoneSlice asserts that no element of s satisfies match.
+func NoneSlice[S ~[]E, E any](t T, s S, match func(E) bool) {
+	t.Helper()
+	if matched := sliceIndexes(s, match); len(matched) > 0 {
+		errorf(t, "NoneSlice", "expected no element to match, %d of %d did:%s",
+			len(matched), len(s), formatIndexed(s, matched))
+	}
+}
+
+// ExactlyNSlice asserts that exactly n elements of s satisfy match.
+func ExactlyNSlice[S ~[]E, E any](t T, s S, n int, match func(E) bool) {
+	t.Helper()
+	if matched := sliceIndexes(s, match); len(matched) != n {
+		errorf(t, "ExactlyNSlice", "expected exactly %d elements to match, got %d:%s",
+			n, len(matched), formatIndexed(s, matched))
+	}
+}
+
+// AllMap asserts that every entry of m satisfies match.
+func AllMap[M ~map[K]V, K comparable, V any](t T, m M, match func(K, V) bool) {
+	t.Helper()
+	if failed := mapKeysFunc(m, func(k K, v V) bool { return !match(k, v) }); len(failed) > 0 {
+		errorf(t, "AllMap", "expected all %d entries to match, %d did not:%s",
+			len(m), len(failed), formatEntries(m, failed))
+	}
+}
+
+// AnyMap asserts that at least one entry of m satisfies match.
+func AnyMap[M ~map[K]V, K comparable, V any](t T, m M, match func(K, V) bool) {
+	t.Helper()
+	if len(mapKeysFunc(m, match)) == 0 {
+		errorf(t, "AnyMap", "expected any entry to match, none of %d did: %v", len(m), m)
+	}
+}
+
+// NoneMap asserts that no entry of m satisfies match.
+func NoneMap[M ~map[K]V, K comparable, V any](t T, m M, match func(K, V) bool) {
+	t.Helper()
+	if matched := mapKeysFunc(m, match); len(matched) > 0 {
+		errorf(t, "NoneMap", "expected no entry to match, %d of %d did:%s",
+			len(matched), len(m), formatEntries(m, matched))
+	}
+}
+
+// ExactlyNMap asserts that exactly n entries of m satisfy match.
+func ExactlyNMap[M ~map[K]V, K comparable, V any](t T, m M, n int, match func(K, V) bool) {
+	t.Helper()
+	if matched := mapKeysFunc(m, match); len(matched) != n {
+		errorf(t, "ExactlyNMap", "expected exactly %d entries to match, got %d:%s",
+			n, len(matched), formatEntries(m, matched))
+	}
+}
+
+func not[E any](match func(E) bool) func(E) bool {
+	return func(e E) bool { return !match(e) }
+}
+
+// sliceIndexes returns the indexes of the elements of s that satisfy match.
+func sliceIndexes[E any](s []E, match func(E) bool) []int {
+	var indexes []int
+	for i, e := range s {
+		if match(e) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// mapKeysFunc returns the sorted keys of the entries of m that satisfy
+// match.
+func mapKeysFunc[M ~map[K]V, K comparable, V any](m M, match func(K, V) bool) []K {
+	var keys []K
+	for k, v := range m {
+		if match(k, v) {
+			keys = append(keys, k)
+		}
+	}
+	sortKeys(keys)
+	return keys
+}
+
+// sortKeys sorts map keys in the order used by failure messages.
+func sortKeys[K comparable](keys []K) {
+	slices.SortFunc(keys, func(a, b K) int {
+		return compareKeys(keyValue(a), keyValue(b))
+	})
+}
+
+// keyValue returns the reflect.Value of a map key, unwrapping non-nil
+// interface keys so they sort and format by their dynamic value.
+func keyValue[K comparable](key K) reflect.Value {
+	v := reflect.ValueOf(&key).Elem()
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+func formatIndexed[E any](s []E, indexes []int) string {
+	var b strings.Builder
+	for n, i := range indexes {
+		if n == maxDifferences {
+			fmt.Fprintf(&b, "\n  ... and %d more", len(indexes)-n)
+			break
+		}
+		fmt.Fprintf(&b, "\n  [%d]: %v", i, s[i])
+	}
+	return b.String()
+}
+
+func formatEntries[M ~map[K]V, K comparable, V any](m M, keys []K) string {
+	var b strings.Builder
+	for n, k := range keys {
+		if n == maxDifferences {
+			fmt.Fprintf(&b, "\n  ... and %d more", len(keys)-n)
+			break
+		}
+		fmt.Fprintf(&b, "\n  [%s]: %v", formatValue(keyValue(k)), m[k])
+	}
+	return b.String()
+}
@@ -0,0 +1,163 @@
+package expect
+
+import (
+	"strings"
+	"testing"
+)
+
+func isEven(n int) bool { return n%2 == 0 }
+
+func hasEvenValue(_ string, n int) bool { return n%2 == 0 }
+
+func TestAllSlice(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		AllSlice(m, []int{2, 4}, isEven)
+		AllSlice(m, []int{}, isEven)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		AllSlice(m, []int{2, 3, 4, 5}, isEven)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+		want := "expected all 4 elements to match, 2 did not:\n  [1]: 3\n  [3]: 5"
+		if m.message != want {
+			t.Errorf("got:\n%s\nwant:\n%s", m.message, want)
+		}
+	})
+}
+
+func TestAnySlice(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		AnySlice(m, []int{1, 2}, isEven)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		AnySlice(m, []int{1, 3}, isEven)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestNoneSlice(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		NoneSlice(m, []string{"go", "rust"}, func(s string) bool { return strings.HasPrefix(s, "j") })
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		NoneSlice(m, []int{1, 2}, isEven)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestExactlyNSlice(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		ExactlyNSlice(m, []int{1, 2, 4}, 2, isEven)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		ExactlyNSlice(m, []int{1, 2, 4}, 1, isEven)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestAllMap(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		AllMap(m, map[string]int{"a": 2, "b": 4}, hasEvenValue)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		AllMap(m, map[string]int{"c": 3, "a": 1, "b": 2}, hasEvenValue)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+		want := "expected all 3 entries to match, 2 did not:\n  [\"a\"]: 1\n  [\"c\"]: 3"
+		if m.message != want {
+			t.Errorf("got:\n%s\nwant:\n%s", m.message, want)
+		}
+	})
+}
+
+func TestAnyMap(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		AnyMap(m, map[string]int{"a": 1, "b": 2}, hasEvenValue)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		AnyMap(m, map[string]int{"a": 1}, hasEvenValue)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestNoneMap(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		NoneMap(m, map[string]int{"a": 1}, hasEvenValue)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		NoneMap(m, map[string]int{"a": 1, "b": 2}, hasEvenValue)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestExactlyNMap(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		ExactlyNMap(m, map[int]int{1: 1, 2: 2, 3: 3}, 1, func(k, v int) bool { return k == 2 })
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		ExactlyNMap(m, map[any]int{nil: 2, "a": 4, 1: 6}, 2, func(_ any, v int) bool { return v%2 == 0 })
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
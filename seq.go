@@ -0,0 +1,262 @@
+package expect
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+)
+
+// maxSeqLen bounds how many elements an assertion consumes from a sequence
+// when the expected values do not bound it, so that infinite sequences
+// fail instead of hanging.
+const maxSeqLen = 1_000_000
+
+// maxShownItems bounds the number of elements of a sequence listed in a
+// failure.
+const maxShownItems = 100
+
+// EqualSeq asserts that seq yields exactly the elements of expected, in
+// order. At most len(expected)+1 elements are consumed.
+//
+//	expect.EqualSeq(t, []string{"a", "b"}, maps.Keys(m))
+func EqualSeq[E comparable](t T, expected []E, seq iter.Seq[E]) {
+	t.Helper()
+	got := consume(seq, len(expected), nil)
+	if !checkYield(t, "EqualSeq", got) {
+		return
+	}
+	if got.truncated || !slices.Equal(expected, got.items) {
+		failMismatch(t, "EqualSeq", formatItems(expected, false), got.String())
+	}
+}
+
+// EqualSeq2 asserts that seq yields the same pairs as expected, in order.
+// At most one pair more than expected yields is consumed from seq, and
+// expected must yield at most a million pairs.
+//
+//	expect.EqualSeq2(t, slices.All([]string{"a", "b"}), list.All())
+func EqualSeq2[K, V comparable](t T, expected, seq iter.Seq2[K, V]) {
+	t.Helper()
+	want := consume(pairs(expected), maxSeqLen, nil)
+	if !checkYield(t, "EqualSeq2", want) || !checkExpectedLen(t, "EqualSeq2", want) {
+		return
+	}
+	got := consume(pairs(seq), len(want.items), nil)
+	if !checkYield(t, "EqualSeq2", got) {
+		return
+	}
+	if got.truncated || !slices.Equal(want.items, got.items) {
+		failMismatch(t, "EqualSeq2", want.String(), got.String())
+	}
+}
+
+// ContainsSeq asserts that seq yields item. Consumption stops at the first
+// match.
+func ContainsSeq[E comparable](t T, seq iter.Seq[E], item E) {
+	t.Helper()
+	found := false
+	got := consume(seq, maxSeqLen, func(e E) bool {
+		found = e == item
+		return found
+	})
+	if !checkYield(t, "ContainsSeq", got) || found {
+		return
+	}
+	if got.truncated {
+		errorf(t, "ContainsSeq", "expected sequence to contain %v, not found in the first %d elements", item, maxSeqLen)
+		return
+	}
+	errorf(t, "ContainsSeq", "expected sequence %v to contain %v", got, item)
+}
+
+// LenSeq asserts that seq yields exactly length elements. At most
+// length+1 elements are consumed.
+func LenSeq[E any](t T, seq iter.Seq[E], length int) {
+	t.Helper()
+	got := consume(seq, length, nil)
+	if !checkYield(t, "LenSeq", got) {
+		return
+	}
+	if got.truncated {
+		errorfValues(t, "LenSeq", length, nil, "expected sequence of length %d, got more: %v", length, got)
+		return
+	}
+	if len(got.items) != length {
+		errorfValues(t, "LenSeq", length, len(got.items), "expected sequence of length %d, got %d: %v", length, len(got.items), got)
+	}
+}
+
+// LenSeq2 asserts that seq yields exactly length pairs. At most length+1
+// pairs are consumed.
+func LenSeq2[K, V any](t T, seq iter.Seq2[K, V], length int) {
+	t.Helper()
+	got := consume(pairs(seq), length, nil)
+	if !checkYield(t, "LenSeq2", got) {
+		return
+	}
+	if got.truncated {
+		errorfValues(t, "LenSeq2", length, nil, "expected sequence of length %d, got more: %v", length, got)
+		return
+	}
+	if len(got.items) != length {
+		errorfValues(t, "LenSeq2", length, len(got.items), "expected sequence of length %d, got %d: %v", length, len(got.items), got)
+	}
+}
+
+// EmptySeq asserts that seq yields no elements. At most one element is
+// consumed.
+func EmptySeq[E any](t T, seq iter.Seq[E]) {
+	t.Helper()
+	got := consume(seq, 0, nil)
+	if checkYield(t, "EmptySeq", got) && got.truncated {
+		errorf(t, "EmptySeq", "expected empty sequence, got %v", got)
+	}
+}
+
+// NotEmptySeq asserts that seq yields at least one element. At most one
+// element is consumed.
+func NotEmptySeq[E any](t T, seq iter.Seq[E]) {
+	t.Helper()
+	got := consume(seq, 0, nil)
+	if checkYield(t, "NotEmptySeq", got) && !got.truncated {
+		errorf(t, "NotEmptySeq", "expected non-empty sequence, got none")
+	}
+}
+
+// ElementsMatchSeq asserts that seq yields the elements of expected in any
+// order, with the same number of occurrences. At most len(expected)+1
+// elements are consumed.
+func ElementsMatchSeq[E comparable](t T, expected []E, seq iter.Seq[E]) {
+	t.Helper()
+	got := consume(seq, len(expected), nil)
+	if !checkYield(t, "ElementsMatchSeq", got) {
+		return
+	}
+	if got.truncated {
+		errorfValues(t, "ElementsMatchSeq", expected, got.String(), "expected elements %s, got more: %v", formatItems(expected, false), got)
+		return
+	}
+
+	counts := map[E]int{}
+	for _, e := range expected {
+		counts[e]++
+	}
+	var extra []E
+	for _, e := range got.items {
+		if counts[e] == 0 {
+			extra = append(extra, e)
+			continue
+		}
+		counts[e]--
+	}
+	var missing []E
+	for _, e := range expected {
+		if counts[e] > 0 {
+			missing = append(missing, e)
+			counts[e]--
+		}
+	}
+	if len(missing) > 0 || len(extra) > 0 {
+		errorfValues(t, "ElementsMatchSeq", expected, got.String(),
+			"expected elements %s in any order, got %s\nmissing: %s\nextra: %s", formatItems(expected, false),
+			formatItems(got.items, false), formatItems(missing, false), formatItems(extra, false))
+	}
+}
+
+// consumed holds the elements an assertion consumed from a sequence.
+type consumed[E any] struct {
+	items []E
+	// truncated reports that the sequence yielded more than the limit, and
+	// that consumption stopped after one more element.
+	truncated bool
+	// yieldAfterStop reports that the sequence called yield again after
+	// yield returned false.
+	yieldAfterStop bool
+}
+
+func (c consumed[E]) String() string {
+	return formatItems(c.items, c.truncated)
+}
+
+// formatItems formats the elements of a sequence, listing at most
+// maxShownItems of them. more reports that the sequence continues past
+// items.
+func formatItems[E any](items []E, more bool) string {
+	shown := items[:min(len(items), maxShownItems)]
+	s := fmt.Sprint(shown)
+	switch {
+	case more:
+		s = strings.TrimSuffix(s, "]") + " ...]"
+	case len(shown) < len(items):
+		s = strings.TrimSuffix(s, "]") + fmt.Sprintf(" ... and %d more]", len(items)-len(shown))
+	}
+	return s
+}
+
+// consume collects up to limit elements of seq, stopping early once done
+// reports true for an element. If seq yields more, the first element past
+// the limit is kept and the result is truncated.
+func consume[E any](seq iter.Seq[E], limit int, done func(E) bool) consumed[E] {
+	var c consumed[E]
+	stopped := false
+	seq(func(e E) bool {
+		switch {
+		case stopped:
+			c.yieldAfterStop = true
+			return false
+		case len(c.items) == limit:
+			c.items = append(c.items, e)
+			c.truncated = true
+			stopped = true
+			return false
+		}
+		c.items = append(c.items, e)
+		if done != nil && done(e) {
+			stopped = true
+			return false
+		}
+		return true
+	})
+	return c
+}
+
+// checkExpectedLen fails if the expected sequence c was consumed from is
+// longer than maxSeqLen, and reports whether it is not.
+func checkExpectedLen[E any](t T, assertion string, c consumed[E]) bool {
+	t.Helper()
+	if c.truncated {
+		errorf(t, assertion, "expected sequence exceeds %d elements", maxSeqLen)
+		return false
+	}
+	return true
+}
+
+// checkYield fails if the sequence misbehaved while it was consumed, and
+// reports whether it behaved.
+func checkYield[E any](t T, assertion string, c consumed[E]) bool {
+	t.Helper()
+	if c.yieldAfterStop {
+		errorf(t, assertion, "sequence called yield after yield returned false, after %v", c)
+		return false
+	}
+	return true
+}
+
+// pair is an element of an iter.Seq2.
+type pair[K, V any] struct {
+	key   K
+	value V
+}
+
+func (p pair[K, V]) String() string {
+	return fmt.Sprintf("%v: %v", p.key, p.value)
+}
+
+func pairs[K, V any](seq iter.Seq2[K, V]) iter.Seq[pair[K, V]] {
+	return func(yield func(pair[K, V]) bool) {
+		seq(func(k K, v V) bool {
+			return yield(pair[K, V]{k, v})
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package expect
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// naturals yields 0, 1, 2, ... forever.
+func naturals(yield func(int) bool) {
+	for i := 0; ; i++ {
+		if !yield(i) {
+			return
+		}
+	}
+}
+
+// naturals2 yields the pairs 0: 0, 1: 1, 2: 2, ... forever.
+func naturals2(yield func(int, int) bool) {
+	for i := 0; ; i++ {
+		if !yield(i, i) {
+			return
+		}
+	}
+}
+
+// ignoresStop yields values without checking the result of yield.
+func ignoresStop(yield func(int) bool) {
+	for i := range 3 {
+		yield(i)
+	}
+}
+
+func TestEqualSeq(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		EqualSeq(m, []int{1, 2, 3}, slices.Values([]int{1, 2, 3}))
+		EqualSeq(m, nil, slices.Values([]int{}))
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		EqualSeq(m, []int{1, 2}, slices.Values([]int{1, 3}))
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail infinite", func(t *testing.T) {
+		m := &mockT{}
+		EqualSeq(m, []int{0, 1}, naturals)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+		if want := "expected [0 1], got [0 1 2 ...]"; m.message != want {
+			t.Errorf("got %q, want %q", m.message, want)
+		}
+	})
+
+	t.Run("fail long", func(t *testing.T) {
+		m := &mockT{}
+		expected := slices.Repeat([]int{0}, 1000)
+		EqualSeq(m, expected, slices.Values(expected[1:]))
+		want := "expected [" + strings.Repeat("0 ", maxShownItems) + "... and 900 more], got [" +
+			strings.Repeat("0 ", maxShownItems) + "... and 899 more]"
+		if m.message != want {
+			t.Errorf("got %q, want %q", m.message, want)
+		}
+	})
+
+	t.Run("fail yield after false", func(t *testing.T) {
+		m := &mockT{}
+		EqualSeq(m, []int{0}, ignoresStop)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestEqualSeq2(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		EqualSeq2(m, slices.All([]string{"a", "b"}), slices.All([]string{"a", "b"}))
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		EqualSeq2(m, slices.All([]string{"a", "b"}), slices.Backward([]string{"b", "a"}))
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail infinite expected", func(t *testing.T) {
+		m := &mockT{}
+		EqualSeq2(m, naturals2, naturals2)
+		if want := "expected sequence exceeds 1000000 elements"; m.message != want {
+			t.Errorf("got %q, want %q", m.message, want)
+		}
+	})
+}
+
+func TestContainsSeq(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		ContainsSeq(m, naturals, 42)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		ContainsSeq(m, maps.Keys(map[string]int{"a": 1}), "b")
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail infinite", func(t *testing.T) {
+		m := &mockT{}
+		ContainsSeq(m, naturals, -1)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestLenSeq(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		LenSeq(m, slices.Values([]int{1, 2}), 2)
+		LenSeq2(m, maps.All(map[string]int{"a": 1}), 1)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		LenSeq(m, slices.Values([]int{1, 2}), 3)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail infinite", func(t *testing.T) {
+		m := &mockT{}
+		LenSeq(m, naturals, 3)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail seq2", func(t *testing.T) {
+		m := &mockT{}
+		LenSeq2(m, maps.All(map[string]int{"a": 1, "b": 2}), 1)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestEmptySeq(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		EmptySeq(m, slices.Values([]int(nil)))
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		EmptySeq(m, naturals)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestNotEmptySeq(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		NotEmptySeq(m, naturals)
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		NotEmptySeq(m, slices.Values([]int{}))
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestElementsMatchSeq(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		ElementsMatchSeq(m, []string{"a", "b", "b"}, slices.Values([]string{"b", "a", "b"}))
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		ElementsMatchSeq(m, []string{"a", "b", "b"}, slices.Values([]string{"b", "a", "c"}))
+		if !m.failed {
+			t.Error("expected fail")
+		}
+		want := "expected elements [a b b] in any order, got [b a c]\nmissing: [b]\nextra: [c]"
+		if m.message != want {
+			t.Errorf("got %q, want %q", m.message, want)
+		}
+	})
+
+	t.Run("fail infinite", func(t *testing.T) {
+		m := &mockT{}
+		ElementsMatchSeq(m, []int{1, 0}, naturals)
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
@@ -0,0 +1,194 @@
+package expect
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// maxStressHistory bounds the number of operations a Stress failure lists.
+const maxStressHistory = 50
+
+// StressConfig configures [Stress]. Zero fields take their defaults.
+type StressConfig struct {
+	// Goroutines is the number of goroutines running operations in each
+	// round. It defaults to runtime.GOMAXPROCS(0).
+	Goroutines int
+	// Iterations is the number of operations each goroutine runs in each
+	// round. It defaults to 100.
+	Iterations int
+	// Rounds is the number of rounds. It defaults to 1.
+	Rounds int
+	// Seed seeds the randomness given to operations and used to pick them.
+	// If it is zero, a random seed is used. The seed is reported on failure;
+	// setting it reproduces the operations each goroutine ran, though not
+	// their interleaving.
+	Seed uint64
+	// Jitter makes goroutines call runtime.Gosched at random before
+	// operations, to vary the interleavings.
+	Jitter bool
+	// Invariant, if not nil, is checked between rounds and after the last
+	// one, while no operation runs.
+	Invariant func(t T)
+}
+
+// StressOp is an operation run by [Stress]. Run is called concurrently
+// from several goroutines. Assertions made with t are reported with the
+// goroutine, iteration and operation that made them; r is the calling
+// goroutine's source of randomness.
+type StressOp struct {
+	Name string
+	Run  func(t T, r *rand.Rand)
+}
+
+// Stress runs ops from several goroutines, in rounds, and reports whether
+// no assertion failed. In each round every goroutine runs a number of
+// operations picked at random from ops; once all goroutines are done, the
+// invariant is checked. Stress stops at the first round with failures and
+// reports them with the seed and the operations that ran last.
+//
+//	expect.Stress(t, expect.StressConfig{
+//		Goroutines: 8,
+//		Rounds:     10,
+//		Jitter:     true,
+//		Invariant: func(t expect.T) {
+//			expect.LessOrEqual(t, cache.Len(), capacity)
+//		},
+//	},
+//		expect.StressOp{Name: "Put", Run: func(t expect.T, r *rand.Rand) { cache.Put(r.IntN(100), "v") }},
+//		expect.StressOp{Name: "Get", Run: func(t expect.T, r *rand.Rand) { cache.Get(r.IntN(100)) }},
+//	)
+func Stress(t T, config StressConfig, ops ...StressOp) bool {
+	t.Helper()
+	if len(ops) == 0 {
+		errorf(t, "Stress", "expected operations to run, got none")
+		return false
+	}
+	goroutines := cmp.Or(config.Goroutines, runtime.GOMAXPROCS(0))
+	iterations := cmp.Or(config.Iterations, 100)
+	rounds := cmp.Or(config.Rounds, 1)
+	seed := cmp.Or(config.Seed, rand.Uint64())
+
+	s := &stress{ops: ops}
+	rands := make([]*rand.Rand, goroutines)
+	for g := range rands {
+		rands[g] = rand.New(rand.NewPCG(seed, uint64(g)))
+	}
+	histories := make([][]stressCall, goroutines)
+	for round := 1; round <= rounds; round++ {
+		var wg sync.WaitGroup
+		for g := range goroutines {
+			wg.Go(func() {
+				histories[g] = s.run(round, g, iterations, rands[g], config.Jitter, histories[g])
+			})
+		}
+		wg.Wait()
+		if config.Invariant != nil {
+			config.Invariant(&stressT{stress: s})
+		}
+		if len(s.failures) > 0 {
+			errorf(t, "Stress", "stress test failed in round %d of %d (seed %d):\n%s\n%s",
+				round, rounds, seed, formatStressFailures(s.failures), formatStressHistory(histories, s.calls.Load()))
+			return false
+		}
+	}
+	return true
+}
+
+// stress holds the state shared by the goroutines of a Stress run.
+type stress struct {
+	ops   []StressOp
+	calls atomic.Uint64
+
+	mu       sync.Mutex
+	failures []string
+}
+
+// stressCall is an operation started by a Stress goroutine.
+type stressCall struct {
+	seq       uint64
+	round     int
+	goroutine int
+	iteration int
+	op        string
+}
+
+func (c stressCall) String() string {
+	return fmt.Sprintf("#%d round %d, goroutine %d, iteration %d: %s", c.seq, c.round, c.goroutine, c.iteration, c.op)
+}
+
+// run runs the operations of goroutine g in a round, and returns its
+// history with them appended, keeping the last maxStressHistory.
+func (s *stress) run(round, g, iterations int, r *rand.Rand, jitter bool, history []stressCall) []stressCall {
+	for i := range iterations {
+		op := s.ops[r.IntN(len(s.ops))]
+		if jitter && r.IntN(2) == 0 {
+			runtime.Gosched()
+		}
+		call := stressCall{seq: s.calls.Add(1), round: round, goroutine: g, iteration: i, op: op.Name}
+		if len(history) == maxStressHistory {
+			history = history[1:]
+		}
+		history = append(history, call)
+		st := &stressT{stress: s, call: &call}
+		if p := catch(func() { op.Run(st, r) }); p != nil {
+			st.Errorf("panicked: %v", p)
+		}
+	}
+	return history
+}
+
+// stressT collects the failures of an operation, or of the invariant when
+// call is nil.
+type stressT struct {
+	stress *stress
+	call   *stressCall
+}
+
+func (t *stressT) Helper() {}
+
+func (t *stressT) Errorf(format string, args ...any) {
+	where := "invariant"
+	if c := t.call; c != nil {
+		where = fmt.Sprintf("goroutine %d, iteration %d, %s", c.goroutine, c.iteration, c.op)
+	}
+	t.stress.mu.Lock()
+	defer t.stress.mu.Unlock()
+	t.stress.failures = append(t.stress.failures, where+": "+fmt.Sprintf(format, args...))
+}
+
+func formatStressFailures(failures []string) string {
+	var b strings.Builder
+	b.WriteString("failures:")
+	for i, f := range failures {
+		if i == maxDifferences {
+			fmt.Fprintf(&b, "\n  ... and %d more", len(failures)-i)
+			break
+		}
+		b.WriteString("\n  " + f)
+	}
+	return b.String()
+}
+
+// formatStressHistory lists the last operations started, in order, out of
+// total.
+func formatStressHistory(histories [][]stressCall, total uint64) string {
+	var calls []stressCall
+	for _, h := range histories {
+		calls = append(calls, h...)
+	}
+	slices.SortFunc(calls, func(a, b stressCall) int { return cmp.Compare(a.seq, b.seq) })
+	calls = calls[max(0, len(calls)-maxStressHistory):]
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "last %d of %d operations started:", len(calls), total)
+	for _, c := range calls {
+		fmt.Fprintf(&b, "\n  %v", c)
+	}
+	return b.String()
+}
@@ -0,0 +1,135 @@
+package expect
+
+import (
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestStress(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		var mu sync.Mutex
+		counts := map[int]int{}
+		var total atomic.Int64
+		rounds := 0
+		ok := Stress(m, StressConfig{
+			Goroutines: 4,
+			Iterations: 50,
+			Rounds:     3,
+			Jitter:     true,
+			Invariant: func(t T) {
+				rounds++
+				sum := 0
+				for _, n := range counts {
+					sum += n
+				}
+				Equal(t, total.Load(), int64(sum))
+			},
+		},
+			StressOp{Name: "Add", Run: func(t T, r *rand.Rand) {
+				mu.Lock()
+				defer mu.Unlock()
+				counts[r.IntN(10)]++
+				total.Add(1)
+			}},
+			StressOp{Name: "Read", Run: func(t T, r *rand.Rand) {
+				mu.Lock()
+				defer mu.Unlock()
+				GreaterOrEqual(t, counts[r.IntN(10)], 0)
+			}},
+		)
+		if !ok || m.failed {
+			t.Errorf("expected pass, got %q", m.message)
+		}
+		if rounds != 3 {
+			t.Errorf("expected invariant checked after each of 3 rounds, got %d", rounds)
+		}
+	})
+
+	t.Run("fail invariant", func(t *testing.T) {
+		m := &mockT{}
+		var n atomic.Int64
+		rounds := 0
+		ok := Stress(m, StressConfig{
+			Goroutines: 2,
+			Iterations: 10,
+			Rounds:     5,
+			Seed:       42,
+			Invariant: func(t T) {
+				rounds++
+				Less(t, n.Load(), 30)
+			},
+		}, StressOp{Name: "Inc", Run: func(T, *rand.Rand) { n.Add(1) }})
+		if ok || !m.failed {
+			t.Fatal("expected failure")
+		}
+		if rounds != 2 {
+			t.Errorf("expected to stop after round 2, got %d rounds", rounds)
+		}
+		for _, want := range []string{
+			"stress test failed in round 2 of 5 (seed 42)",
+			"invariant: expected 40 < 30",
+			"last 40 of 40 operations started:",
+			"#40 round 2, goroutine ",
+		} {
+			if !strings.Contains(m.message, want) {
+				t.Errorf("expected message to contain %q, got %q", want, m.message)
+			}
+		}
+	})
+
+	t.Run("fail operation", func(t *testing.T) {
+		m := &mockT{}
+		Stress(m, StressConfig{Goroutines: 1, Iterations: 3}, StressOp{Name: "Check", Run: func(t T, _ *rand.Rand) {
+			True(t, false)
+		}})
+		if !strings.Contains(m.message, "goroutine 0, iteration 0, Check: expected true") {
+			t.Errorf("expected failure with its operation, got %q", m.message)
+		}
+	})
+
+	t.Run("fail panic", func(t *testing.T) {
+		m := &mockT{}
+		Stress(m, StressConfig{Goroutines: 2, Iterations: 1}, StressOp{Name: "Boom", Run: func(T, *rand.Rand) {
+			panic("boom")
+		}})
+		if !strings.Contains(m.message, "Boom: panicked: boom") {
+			t.Errorf("expected panic reported, got %q", m.message)
+		}
+	})
+
+	t.Run("fail many", func(t *testing.T) {
+		m := &mockT{}
+		Stress(m, StressConfig{Goroutines: 1, Iterations: 200}, StressOp{Name: "Fail", Run: func(t T, _ *rand.Rand) {
+			t.Errorf("failed")
+		}})
+		if !strings.Contains(m.message, "... and 190 more") ||
+			!strings.Contains(m.message, "last 50 of 200 operations started:\n  #151 ") {
+			t.Errorf("expected bounded failures and history, got %q", m.message)
+		}
+	})
+
+	t.Run("fail no operations", func(t *testing.T) {
+		m := &mockT{}
+		if Stress(m, StressConfig{}) || !m.failed {
+			t.Error("expected failure")
+		}
+	})
+
+	t.Run("seed reproduces operations", func(t *testing.T) {
+		picks := func() []string {
+			var picked []string
+			op := func(name string) StressOp {
+				return StressOp{Name: name, Run: func(T, *rand.Rand) { picked = append(picked, name) }}
+			}
+			Stress(&mockT{}, StressConfig{Goroutines: 1, Iterations: 20, Seed: 7}, op("a"), op("b"), op("c"))
+			return picked
+		}
+		if first, second := picks(), picks(); strings.Join(first, "") != strings.Join(second, "") {
+			t.Errorf("expected the same operations, got %v and %v", first, second)
+		}
+	})
+}
@@ -0,0 +1,174 @@
+package expect
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// IsType asserts that the dynamic type of value is V, and returns value
+// converted to V. V is usually a concrete type; use [Implements] to check
+// interfaces.
+//
+//	if user, ok := expect.IsType[*User](t, result); ok {
+//		expect.Equal(t, "gopher", user.Name)
+//	}
+func IsType[V any](t T, value any) (V, bool) {
+	t.Helper()
+	v, ok := value.(V)
+	if !ok {
+		typ := reflect.TypeFor[V]()
+		errorfValues(t, "IsType", typ, reflect.TypeOf(value), "expected value of type %v, got %s", typ, describeType(value))
+	}
+	return v, ok
+}
+
+// Implements asserts that value implements the interface I, and returns
+// value converted to I. On failure, the message lists the methods the
+// dynamic type of value is missing.
+func Implements[I any](t T, value any) (I, bool) {
+	t.Helper()
+	iface := reflect.TypeFor[I]()
+	if iface.Kind() != reflect.Interface {
+		errorf(t, "Implements", "expected an interface type, got %v", iface)
+		var zero I
+		return zero, false
+	}
+	v, ok := value.(I)
+	if !ok {
+		failImplements(t, "Implements", value, iface)
+	}
+	return v, ok
+}
+
+// IsTypeOf asserts that the dynamic type of value is typ. It is the
+// reflection counterpart of [IsType] for types only known at run time.
+func IsTypeOf(t T, value any, typ reflect.Type) {
+	t.Helper()
+	if reflect.TypeOf(value) != typ {
+		errorfValues(t, "IsTypeOf", typ, reflect.TypeOf(value), "expected value of type %v, got %s", typ, describeType(value))
+	}
+}
+
+// ImplementsType asserts that the dynamic type of value implements the
+// interface type iface. It is the reflection counterpart of [Implements].
+func ImplementsType(t T, value any, iface reflect.Type) {
+	t.Helper()
+	if iface == nil || iface.Kind() != reflect.Interface {
+		errorf(t, "ImplementsType", "expected an interface type, got %v", iface)
+		return
+	}
+	typ := reflect.TypeOf(value)
+	if typ == nil || !typ.Implements(iface) {
+		failImplements(t, "ImplementsType", value, iface)
+	}
+}
+
+// AssignableTo asserts that value can be assigned to a variable of type typ.
+func AssignableTo(t T, value any, typ reflect.Type) {
+	t.Helper()
+	if typ == nil {
+		errorf(t, "AssignableTo", "expected a type, got nil")
+		return
+	}
+	actual := reflect.TypeOf(value)
+	if actual == nil {
+		if !canBeNil(typ) {
+			errorf(t, "AssignableTo", "expected value assignable to %v, got nil", typ)
+		}
+		return
+	}
+	if !actual.AssignableTo(typ) {
+		msg := fmt.Sprintf("expected value assignable to %v, got %s", typ, describeType(value))
+		if typ.Kind() == reflect.Interface {
+			msg += "\n" + formatMissingMethods(actual, typ)
+		}
+		errorf(t, "AssignableTo", "%s", msg)
+	}
+}
+
+func canBeNil(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+func failImplements(t T, assertion string, value any, iface reflect.Type) {
+	t.Helper()
+	typ := reflect.TypeOf(value)
+	if typ == nil {
+		errorf(t, assertion, "expected value implementing %v, got nil", iface)
+		return
+	}
+	errorf(t, assertion, "expected %v to implement %v\n%s", typ, iface, formatMissingMethods(typ, iface))
+}
+
+// describeType formats the dynamic type of value for failure messages.
+func describeType(value any) string {
+	if value == nil {
+		return "nil"
+	}
+	return fmt.Sprintf("%v (%T)", value, value)
+}
+
+// formatMissingMethods lists the methods of iface that typ lacks or has with
+// a different signature.
+func formatMissingMethods(typ, iface reflect.Type) string {
+	var b strings.Builder
+	b.WriteString("missing methods:")
+	for i := range iface.NumMethod() {
+		want := iface.Method(i)
+		wantSig := methodSignature(want.Type, false)
+		got, ok := typ.MethodByName(want.Name)
+		switch {
+		case !ok && typ.Kind() != reflect.Pointer && typ.Kind() != reflect.Interface && hasMethod(reflect.PointerTo(typ), want.Name):
+			fmt.Fprintf(&b, "\n  %s%s (method has pointer receiver; use *%v)", want.Name, wantSig, typ)
+		case !ok:
+			fmt.Fprintf(&b, "\n  %s%s", want.Name, wantSig)
+		default:
+			gotSig := methodSignature(got.Type, typ.Kind() != reflect.Interface)
+			if gotSig != wantSig {
+				fmt.Fprintf(&b, "\n  %s%s (have %s%s)", want.Name, wantSig, want.Name, gotSig)
+			}
+		}
+	}
+	return b.String()
+}
+
+func hasMethod(typ reflect.Type, name string) bool {
+	_, ok := typ.MethodByName(name)
+	return ok
+}
+
+// methodSignature formats a method type without the func keyword, dropping
+// the receiver for methods of concrete types.
+func methodSignature(fn reflect.Type, hasReceiver bool) string {
+	first := 0
+	if hasReceiver {
+		first = 1
+	}
+	in := make([]string, 0, fn.NumIn())
+	for i := first; i < fn.NumIn(); i++ {
+		if fn.IsVariadic() && i == fn.NumIn()-1 {
+			in = append(in, "..."+fn.In(i).Elem().String())
+			continue
+		}
+		in = append(in, fn.In(i).String())
+	}
+	out := make([]string, fn.NumOut())
+	for i := range out {
+		out[i] = fn.Out(i).String()
+	}
+
+	sig := "(" + strings.Join(in, ", ") + ")"
+	switch len(out) {
+	case 0:
+	case 1:
+		sig += " " + out[0]
+	default:
+		sig += " (" + strings.Join(out, ", ") + ")"
+	}
+	return sig
+}
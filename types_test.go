@@ -0,0 +1,210 @@
+package expect
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type shape interface {
+	Area() float64
+	Scale(factor float64) shape
+}
+
+type square struct{ side float64 }
+
+func (s square) Area() float64 { return s.side * s.side }
+
+type circle struct{ radius float64 }
+
+func (c *circle) Area() float64 { return 3 * c.radius * c.radius }
+
+func (c *circle) Scale(factor float64) shape { return &circle{c.radius * factor} }
+
+type badReader struct{}
+
+func (badReader) Read(p []byte) int { return 0 }
+
+func TestIsType(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		got, ok := IsType[*circle](m, shape(&circle{radius: 2}))
+		if m.failed || !ok {
+			t.Error("expected pass")
+		}
+		if got == nil || got.radius != 2 {
+			t.Errorf("expected converted value, got %v", got)
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		got, ok := IsType[string](m, 42)
+		if !m.failed || ok {
+			t.Error("expected fail")
+		}
+		if got != "" {
+			t.Errorf("expected zero value, got %q", got)
+		}
+	})
+
+	t.Run("fail nil", func(t *testing.T) {
+		m := &mockT{}
+		if _, ok := IsType[*circle](m, nil); !m.failed || ok {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestImplements(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		got, ok := Implements[shape](m, &circle{radius: 1})
+		if m.failed || !ok {
+			t.Error("expected pass")
+		}
+		if got == nil || got.Area() != 3 {
+			t.Errorf("expected converted value, got %v", got)
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		if _, ok := Implements[shape](m, square{side: 1}); !m.failed || ok {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail pointer receiver", func(t *testing.T) {
+		m := &mockT{}
+		if _, ok := Implements[shape](m, circle{}); !m.failed || ok {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail nil", func(t *testing.T) {
+		m := &mockT{}
+		if _, ok := Implements[io.Reader](m, nil); !m.failed || ok {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail not interface", func(t *testing.T) {
+		m := &mockT{}
+		if _, ok := Implements[square](m, square{}); !m.failed || ok {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestIsTypeOf(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		IsTypeOf(m, fmt.Errorf("wrapped: %w", io.EOF), reflect.TypeOf(fmt.Errorf("%w", io.EOF)))
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		IsTypeOf(m, errors.New("plain"), reflect.TypeFor[*customError]())
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestImplementsType(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		ImplementsType(m, &strings.Reader{}, reflect.TypeFor[io.Reader]())
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		ImplementsType(m, badReader{}, reflect.TypeFor[io.Reader]())
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail not interface", func(t *testing.T) {
+		m := &mockT{}
+		ImplementsType(m, 1, reflect.TypeFor[int]())
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestAssignableTo(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		m := &mockT{}
+		AssignableTo(m, &circle{}, reflect.TypeFor[shape]())
+		AssignableTo(m, 1, reflect.TypeFor[int]())
+		AssignableTo(m, nil, reflect.TypeFor[error]())
+		if m.failed {
+			t.Error("expected pass")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := &mockT{}
+		AssignableTo(m, int32(1), reflect.TypeFor[int]())
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+
+	t.Run("fail nil", func(t *testing.T) {
+		m := &mockT{}
+		AssignableTo(m, nil, reflect.TypeFor[int]())
+		if !m.failed {
+			t.Error("expected fail")
+		}
+	})
+}
+
+func TestFormatMissingMethods(t *testing.T) {
+	tests := []struct {
+		name  string
+		typ   reflect.Type
+		iface reflect.Type
+		want  string
+	}{
+		{
+			name:  "missing",
+			typ:   reflect.TypeFor[square](),
+			iface: reflect.TypeFor[shape](),
+			want:  "missing methods:\n  Scale(float64) expect.shape",
+		},
+		{
+			name:  "pointer receiver",
+			typ:   reflect.TypeFor[circle](),
+			iface: reflect.TypeFor[shape](),
+			want: "missing methods:\n" +
+				"  Area() float64 (method has pointer receiver; use *expect.circle)\n" +
+				"  Scale(float64) expect.shape (method has pointer receiver; use *expect.circle)",
+		},
+		{
+			name:  "wrong signature",
+			typ:   reflect.TypeFor[badReader](),
+			iface: reflect.TypeFor[io.Reader](),
+			want:  "missing methods:\n  Read([]uint8) (int, error) (have Read([]uint8) int)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatMissingMethods(tt.typ, tt.iface); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}